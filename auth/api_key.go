@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const (
+	HeaderAPIKey = "X-API-Key"
+
+	hashPrefix = "sha256:"
+)
+
+var (
+	ErrInvalidKeyHash  = errors.New("invalid API key hash")
+	ErrDuplicateKey    = errors.New("duplicate API key")
+	ErrReadingKeysFile = errors.New("reading API keys file")
+)
+
+type (
+	// APIKey describes one accepted key. Only the SHA-256 of the key is kept,
+	// written as "sha256:<hex>", so config files never hold usable secrets.
+	APIKey struct {
+		Name   string   `json:"name"`
+		Hash   string   `json:"hash"`
+		Scopes []string `json:"scopes"`
+	}
+
+	APIKeyAuthenticator struct {
+		keys map[[sha256.Size]byte]Principal
+	}
+)
+
+func NewAPIKeyAuthenticator(keys []APIKey) (*APIKeyAuthenticator, error) {
+	a := &APIKeyAuthenticator{keys: make(map[[sha256.Size]byte]Principal, len(keys))}
+	for _, key := range keys {
+		digest, err := parseKeyHash(key.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %q: %w", ErrInvalidKeyHash, key.Name, err)
+		}
+		if _, ok := a.keys[digest]; ok {
+			return nil, fmt.Errorf("%w: key %q", ErrDuplicateKey, key.Name)
+		}
+		a.keys[digest] = Principal{Name: key.Name, Scopes: key.Scopes}
+	}
+	return a, nil
+}
+
+// LoadAPIKeys reads a JSON array of APIKey entries from path.
+func LoadAPIKeys(path string) ([]APIKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReadingKeysFile, err)
+	}
+	var keys []APIKey
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReadingKeysFile, err)
+	}
+	return keys, nil
+}
+
+// HashAPIKey returns the value to store in APIKey.Hash for key.
+func HashAPIKey(key string) string {
+	digest := sha256.Sum256([]byte(key))
+	return hashPrefix + hex.EncodeToString(digest[:])
+}
+
+func (a *APIKeyAuthenticator) Challenge() string {
+	return `APIKey header="` + HeaderAPIKey + `"`
+}
+
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (Principal, error) {
+	key := r.Header.Get(HeaderAPIKey)
+	if key == "" {
+		return Principal{}, ErrMissingCredentials
+	}
+	digest := sha256.Sum256([]byte(key))
+	for stored, principal := range a.keys {
+		if subtle.ConstantTimeCompare(stored[:], digest[:]) == 1 {
+			return principal, nil
+		}
+	}
+	return Principal{}, ErrInvalidCredentials
+}
+
+func parseKeyHash(hash string) ([sha256.Size]byte, error) {
+	var digest [sha256.Size]byte
+	encoded, ok := strings.CutPrefix(hash, hashPrefix)
+	if !ok {
+		return digest, fmt.Errorf("missing %q prefix", hashPrefix)
+	}
+	decoded, err := hex.DecodeString(encoded)
+	if err != nil {
+		return digest, err
+	}
+	if len(decoded) != sha256.Size {
+		return digest, fmt.Errorf("expected %d bytes, got %d", sha256.Size, len(decoded))
+	}
+	copy(digest[:], decoded)
+	return digest, nil
+}
@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testKey       = "s3cr3t-key"
+	otherKey      = "another-key"
+	testKeyName   = "reporting"
+	scopeReadOnly = "books:read"
+)
+
+func newRequestWithKey(key string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/books", nil)
+	if key != "" {
+		req.Header.Set(HeaderAPIKey, key)
+	}
+	return req
+}
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	authenticator, err := NewAPIKeyAuthenticator([]APIKey{
+		{Name: testKeyName, Hash: HashAPIKey(testKey), Scopes: []string{scopeReadOnly}},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		key      string
+		expected Principal
+		err      error
+	}{
+		{"valid key", testKey, Principal{Name: testKeyName, Scopes: []string{scopeReadOnly}}, nil},
+		{"unknown key", otherKey, Principal{}, ErrInvalidCredentials},
+		{"missing key", "", Principal{}, ErrMissingCredentials},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := authenticator.Authenticate(newRequestWithKey(tt.key))
+
+			require.ErrorIs(t, err, tt.err)
+			require.Equal(t, tt.expected, principal)
+		})
+	}
+}
+
+func TestNewAPIKeyAuthenticator_RejectsBadHashes(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []APIKey
+		err  error
+	}{
+		{"missing prefix", []APIKey{{Name: testKeyName, Hash: "abc"}}, ErrInvalidKeyHash},
+		{"not hex", []APIKey{{Name: testKeyName, Hash: "sha256:zz"}}, ErrInvalidKeyHash},
+		{"wrong length", []APIKey{{Name: testKeyName, Hash: "sha256:abcd"}}, ErrInvalidKeyHash},
+		{"duplicate", []APIKey{
+			{Name: testKeyName, Hash: HashAPIKey(testKey)},
+			{Name: "copy", Hash: HashAPIKey(testKey)},
+		}, ErrDuplicateKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAPIKeyAuthenticator(tt.keys)
+
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestLoadAPIKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	content := `[{"name": "reporting", "hash": "` + HashAPIKey(testKey) + `", "scopes": ["books:read"]}]`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	keys, err := LoadAPIKeys(path)
+
+	require.NoError(t, err)
+	require.Equal(t, []APIKey{{Name: testKeyName, Hash: HashAPIKey(testKey), Scopes: []string{scopeReadOnly}}}, keys)
+}
+
+func TestLoadAPIKeys_Errors(t *testing.T) {
+	malformed := filepath.Join(t.TempDir(), "keys.json")
+	require.NoError(t, os.WriteFile(malformed, []byte(`{`), 0o600))
+
+	_, missingErr := LoadAPIKeys(filepath.Join(t.TempDir(), "missing.json"))
+	_, malformedErr := LoadAPIKeys(malformed)
+
+	require.ErrorIs(t, missingErr, ErrReadingKeysFile)
+	require.ErrorIs(t, malformedErr, ErrReadingKeysFile)
+}
@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+)
+
+const (
+	ScopeBooksRead  = "books:read"
+	ScopeBooksWrite = "books:write"
+	// ScopeAdmin grants every other scope.
+	ScopeAdmin = "admin"
+)
+
+var (
+	ErrMissingCredentials = errors.New("missing credentials")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+type (
+	Principal struct {
+		Name   string
+		Scopes []string
+	}
+
+	// Authenticator identifies the caller of a request. It returns
+	// ErrMissingCredentials when the request carries none of the credentials
+	// it understands, so several authenticators can be chained.
+	Authenticator interface {
+		Authenticate(r *http.Request) (Principal, error)
+	}
+
+	// Challenger names the credentials an authenticator expects, as a
+	// WWW-Authenticate challenge sent with 401 responses.
+	Challenger interface {
+		Challenge() string
+	}
+
+	principalKey struct{}
+)
+
+func (p Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope) || slices.Contains(p.Scopes, ScopeAdmin)
+}
+
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+type chain []Authenticator
+
+// Chain tries each authenticator in order and uses the first one that finds
+// credentials it understands. A request is only rejected as missing
+// credentials when none of them found any.
+func Chain(authenticators ...Authenticator) Authenticator {
+	if len(authenticators) == 1 {
+		return authenticators[0]
+	}
+	return chain(authenticators)
+}
+
+func (c chain) Authenticate(r *http.Request) (Principal, error) {
+	for _, authenticator := range c {
+		principal, err := authenticator.Authenticate(r)
+		if errors.Is(err, ErrMissingCredentials) {
+			continue
+		}
+		return principal, err
+	}
+	return Principal{}, ErrMissingCredentials
+}
+
+// Challenge lists the challenge of every authenticator in the chain, since
+// any of their credentials would be accepted.
+func (c chain) Challenge() string {
+	challenges := make([]string, 0, len(c))
+	for _, authenticator := range c {
+		if challenger, ok := authenticator.(Challenger); ok {
+			challenges = append(challenges, challenger.Challenge())
+		}
+	}
+	return strings.Join(challenges, ", ")
+}
@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChain(t *testing.T) {
+	fixture := newJWTFixture(t)
+	apiKeys, err := NewAPIKeyAuthenticator([]APIKey{{Name: testKeyName, Hash: HashAPIKey(testKey)}})
+	require.NoError(t, err)
+	authenticator := Chain(apiKeys, fixture.authenticator)
+
+	withKey := newRequestWithKey(testKey)
+	withToken := bearerRequest(sign(t, jwt.SigningMethodHS256, hmacKeyID, testHMACSecret, validClaims()))
+	withBadKey := newRequestWithKey(otherKey)
+	withBadKey.Header.Set(headerAuthorization, withToken.Header.Get(headerAuthorization))
+	withNothing := httptest.NewRequest(http.MethodGet, "/v2/books", nil)
+
+	keyPrincipal, keyErr := authenticator.Authenticate(withKey)
+	tokenPrincipal, tokenErr := authenticator.Authenticate(withToken)
+	_, badKeyErr := authenticator.Authenticate(withBadKey)
+	_, nothingErr := authenticator.Authenticate(withNothing)
+
+	require.NoError(t, keyErr)
+	require.Equal(t, testKeyName, keyPrincipal.Name)
+	require.NoError(t, tokenErr)
+	require.Equal(t, testSubject, tokenPrincipal.Name)
+	require.ErrorIs(t, badKeyErr, ErrInvalidCredentials)
+	require.ErrorIs(t, nothingErr, ErrMissingCredentials)
+}
+
+func TestChain_ChallengesForEveryScheme(t *testing.T) {
+	fixture := newJWTFixture(t)
+	apiKeys, err := NewAPIKeyAuthenticator(nil)
+	require.NoError(t, err)
+
+	challenger, ok := Chain(apiKeys, fixture.authenticator).(Challenger)
+
+	require.True(t, ok)
+	require.Equal(t, `APIKey header="X-API-Key", Bearer`, challenger.Challenge())
+}
+
+func TestPrincipal_HasScope(t *testing.T) {
+	reader := Principal{Scopes: []string{ScopeBooksRead}}
+	admin := Principal{Scopes: []string{ScopeAdmin}}
+
+	require.True(t, reader.HasScope(ScopeBooksRead))
+	require.False(t, reader.HasScope(ScopeBooksWrite))
+	require.True(t, admin.HasScope(ScopeBooksWrite))
+}
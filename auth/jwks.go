@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+const (
+	keyTypeRSA       = "RSA"
+	keyTypeSymmetric = "oct"
+
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+)
+
+var (
+	ErrReadingJWKS = errors.New("reading JWKS file")
+	ErrInvalidJWK  = errors.New("invalid JWK")
+)
+
+type (
+	// KeySet holds the verification keys of a JWKS document indexed by key ID.
+	KeySet map[string]VerificationKey
+
+	VerificationKey struct {
+		Algorithm string
+		Key       interface{}
+	}
+
+	jwks struct {
+		Keys []jwk `json:"keys"`
+	}
+
+	jwk struct {
+		KeyID     string `json:"kid"`
+		KeyType   string `json:"kty"`
+		Algorithm string `json:"alg"`
+		Use       string `json:"use"`
+		N         string `json:"n"`
+		E         string `json:"e"`
+		K         string `json:"k"`
+	}
+)
+
+// LoadJWKS reads a JSON Web Key Set from path. Only RSA keys (verified with
+// RS256) and symmetric keys (verified with HS256) are supported; encryption
+// keys are ignored.
+func LoadJWKS(path string) (KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReadingJWKS, err)
+	}
+	return ParseJWKS(data)
+}
+
+func ParseJWKS(data []byte) (KeySet, error) {
+	var document jwks
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReadingJWKS, err)
+	}
+	keys := make(KeySet, len(document.Keys))
+	for _, raw := range document.Keys {
+		if raw.Use == "enc" {
+			continue
+		}
+		key, err := raw.verificationKey()
+		if err != nil {
+			return nil, fmt.Errorf("%w: kid %q: %w", ErrInvalidJWK, raw.KeyID, err)
+		}
+		keys[raw.KeyID] = key
+	}
+	return keys, nil
+}
+
+func (k jwk) verificationKey() (VerificationKey, error) {
+	switch k.KeyType {
+	case keyTypeRSA:
+		if k.Algorithm != "" && k.Algorithm != AlgorithmRS256 {
+			return VerificationKey{}, fmt.Errorf("unsupported algorithm %q", k.Algorithm)
+		}
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return VerificationKey{}, fmt.Errorf("modulus: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return VerificationKey{}, fmt.Errorf("exponent: %w", err)
+		}
+		if !e.IsInt64() || e.Int64() < 2 {
+			return VerificationKey{}, errors.New("exponent out of range")
+		}
+		return VerificationKey{
+			Algorithm: AlgorithmRS256,
+			Key:       &rsa.PublicKey{N: n, E: int(e.Int64())},
+		}, nil
+	case keyTypeSymmetric:
+		if k.Algorithm != "" && k.Algorithm != AlgorithmHS256 {
+			return VerificationKey{}, fmt.Errorf("unsupported algorithm %q", k.Algorithm)
+		}
+		secret, err := base64.RawURLEncoding.DecodeString(k.K)
+		if err != nil || len(secret) == 0 {
+			return VerificationKey{}, errors.New("missing or malformed key")
+		}
+		return VerificationKey{Algorithm: AlgorithmHS256, Key: secret}, nil
+	default:
+		return VerificationKey{}, fmt.Errorf("unsupported key type %q", k.KeyType)
+	}
+}
+
+func decodeBigInt(encoded string) (*big.Int, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(decoded), nil
+}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func TestLoadJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	document := fmt.Sprintf(`{"keys": [
+		{"kid": "rsa-1", "kty": "RSA", "alg": "RS256", "use": "sig", "n": %q, "e": %q},
+		{"kid": "hmac-1", "kty": "oct", "k": %q},
+		{"kid": "enc-1", "kty": "RSA", "use": "enc", "n": "", "e": ""}
+	]}`, encode(rsaKey.N.Bytes()), encode(big.NewInt(int64(rsaKey.E)).Bytes()), encode(testHMACSecret))
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, []byte(document), 0o600))
+
+	keys, err := LoadJWKS(path)
+
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	require.Equal(t, VerificationKey{Algorithm: AlgorithmRS256, Key: &rsaKey.PublicKey}, keys["rsa-1"])
+	require.Equal(t, VerificationKey{Algorithm: AlgorithmHS256, Key: testHMACSecret}, keys["hmac-1"])
+}
+
+func TestParseJWKS_InvalidKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		err      error
+	}{
+		{"malformed document", `{"keys": [`, ErrReadingJWKS},
+		{"unsupported key type", `{"keys": [{"kid": "ec", "kty": "EC"}]}`, ErrInvalidJWK},
+		{"algorithm mismatch", `{"keys": [{"kid": "h", "kty": "oct", "alg": "RS256", "k": "c2VjcmV0"}]}`, ErrInvalidJWK},
+		{"empty secret", `{"keys": [{"kid": "h", "kty": "oct"}]}`, ErrInvalidJWK},
+		{"missing modulus", `{"keys": [{"kid": "r", "kty": "RSA", "e": "AQAB"}]}`, ErrInvalidJWK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseJWKS([]byte(tt.document))
+
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestLoadJWKS_MissingFile(t *testing.T) {
+	_, err := LoadJWKS(filepath.Join(t.TempDir(), "missing.json"))
+
+	require.ErrorIs(t, err, ErrReadingJWKS)
+}
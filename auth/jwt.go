@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	headerAuthorization = "Authorization"
+	bearerPrefix        = "Bearer "
+	challengeBearer     = "Bearer"
+)
+
+type (
+	JWTOptions struct {
+		Issuer   string
+		Audience string
+		Leeway   time.Duration
+	}
+
+	JWTAuthenticator struct {
+		keys   KeySet
+		parser *jwt.Parser
+	}
+
+	// claims accepts scopes either as an OAuth 2.0 space-separated "scope"
+	// string or as an "scp" array, which is what most identity providers emit.
+	claims struct {
+		jwt.RegisteredClaims
+		Scope string   `json:"scope"`
+		Scp   []string `json:"scp"`
+	}
+)
+
+func NewJWTAuthenticator(keys KeySet, opts JWTOptions) *JWTAuthenticator {
+	return &JWTAuthenticator{
+		keys: keys,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods([]string{AlgorithmHS256, AlgorithmRS256}),
+			jwt.WithIssuer(opts.Issuer),
+			jwt.WithAudience(opts.Audience),
+			jwt.WithExpirationRequired(),
+			jwt.WithLeeway(opts.Leeway),
+		),
+	}
+}
+
+func (a *JWTAuthenticator) Challenge() string {
+	return challengeBearer
+}
+
+func (a *JWTAuthenticator) Authenticate(r *http.Request) (Principal, error) {
+	header := r.Header.Get(headerAuthorization)
+	if len(header) < len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+		return Principal{}, ErrMissingCredentials
+	}
+
+	var parsed claims
+	if _, err := a.parser.ParseWithClaims(header[len(bearerPrefix):], &parsed, a.keyFor); err != nil {
+		return Principal{}, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+	}
+	scopes := parsed.Scp
+	if parsed.Scope != "" {
+		scopes = append(scopes, strings.Fields(parsed.Scope)...)
+	}
+	return Principal{Name: parsed.Subject, Scopes: scopes}, nil
+}
+
+// keyFor picks the verification key named by the token's kid header and makes
+// sure the token uses the algorithm that key is meant for, so an RSA public
+// key can never be used as an HMAC secret.
+func (a *JWTAuthenticator) keyFor(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := a.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("algorithm %s does not match key %q", token.Method.Alg(), kid)
+	}
+	return key.Key, nil
+}
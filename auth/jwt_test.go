@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIssuer   = "https://sso.example.com"
+	testAudience = "bookshop"
+	testSubject  = "alice"
+	hmacKeyID    = "hmac-1"
+	rsaKeyID     = "rsa-1"
+)
+
+var testHMACSecret = []byte("0123456789abcdef0123456789abcdef")
+
+type jwtFixture struct {
+	authenticator *JWTAuthenticator
+	rsaKey        *rsa.PrivateKey
+}
+
+func newJWTFixture(t *testing.T) jwtFixture {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keys := KeySet{
+		hmacKeyID: {Algorithm: AlgorithmHS256, Key: testHMACSecret},
+		rsaKeyID:  {Algorithm: AlgorithmRS256, Key: &rsaKey.PublicKey},
+	}
+	return jwtFixture{
+		authenticator: NewJWTAuthenticator(keys, JWTOptions{Issuer: testIssuer, Audience: testAudience}),
+		rsaKey:        rsaKey,
+	}
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":   testIssuer,
+		"aud":   testAudience,
+		"sub":   testSubject,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "books:read books:write",
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func bearerRequest(token string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/v2/books", nil)
+	req.Header.Set(headerAuthorization, bearerPrefix+token)
+	return req
+}
+
+func TestJWTAuthenticator_ValidTokens(t *testing.T) {
+	fixture := newJWTFixture(t)
+	tokens := map[string]string{
+		"HS256": sign(t, jwt.SigningMethodHS256, hmacKeyID, testHMACSecret, validClaims()),
+		"RS256": sign(t, jwt.SigningMethodRS256, rsaKeyID, fixture.rsaKey, validClaims()),
+	}
+
+	for name, token := range tokens {
+		t.Run(name, func(t *testing.T) {
+			principal, err := fixture.authenticator.Authenticate(bearerRequest(token))
+
+			require.NoError(t, err)
+			require.Equal(t, Principal{Name: testSubject, Scopes: []string{ScopeBooksRead, ScopeBooksWrite}}, principal)
+		})
+	}
+}
+
+func TestJWTAuthenticator_ScpArrayClaim(t *testing.T) {
+	fixture := newJWTFixture(t)
+	claims := validClaims()
+	delete(claims, "scope")
+	claims["scp"] = []string{ScopeAdmin}
+
+	principal, err := fixture.authenticator.Authenticate(bearerRequest(sign(t, jwt.SigningMethodHS256, hmacKeyID, testHMACSecret, claims)))
+
+	require.NoError(t, err)
+	require.Equal(t, []string{ScopeAdmin}, principal.Scopes)
+}
+
+func TestJWTAuthenticator_RejectsInvalidTokens(t *testing.T) {
+	fixture := newJWTFixture(t)
+	with := func(key string, value interface{}) jwt.MapClaims {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"expired", sign(t, jwt.SigningMethodHS256, hmacKeyID, testHMACSecret, with("exp", time.Now().Add(-time.Hour).Unix()))},
+		{"missing expiry", sign(t, jwt.SigningMethodHS256, hmacKeyID, testHMACSecret, with("exp", nil))},
+		{"wrong issuer", sign(t, jwt.SigningMethodHS256, hmacKeyID, testHMACSecret, with("iss", "https://evil.example.com"))},
+		{"wrong audience", sign(t, jwt.SigningMethodHS256, hmacKeyID, testHMACSecret, with("aud", "other-service"))},
+		{"unknown key id", sign(t, jwt.SigningMethodHS256, "rotated-out", testHMACSecret, validClaims())},
+		{"wrong secret", sign(t, jwt.SigningMethodHS256, hmacKeyID, []byte("not-the-secret"), validClaims())},
+		{"algorithm mismatch", sign(t, jwt.SigningMethodHS256, rsaKeyID, testHMACSecret, validClaims())},
+		{"unsupported algorithm", sign(t, jwt.SigningMethodHS512, hmacKeyID, testHMACSecret, validClaims())},
+		{"malformed", "not.a.jwt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := fixture.authenticator.Authenticate(bearerRequest(tt.token))
+
+			require.ErrorIs(t, err, ErrInvalidCredentials)
+		})
+	}
+}
+
+func TestJWTAuthenticator_MissingBearer(t *testing.T) {
+	fixture := newJWTFixture(t)
+	req := httptest.NewRequest(http.MethodGet, "/v2/books", nil)
+	req.Header.Set(headerAuthorization, "Basic dXNlcjpwYXNz")
+
+	_, err := fixture.authenticator.Authenticate(req)
+
+	require.ErrorIs(t, err, ErrMissingCredentials)
+}
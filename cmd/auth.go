@@ -0,0 +1,72 @@
+package main
+
+import (
+	"educabot.com/bookshop/auth"
+	"educabot.com/bookshop/config"
+	"educabot.com/bookshop/handler"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	authMethodAPIKey = "api_key"
+	authMethodJWT    = "jwt"
+)
+
+func newAuthenticator(cfg config.AuthConfig) (auth.Authenticator, error) {
+	authenticators := make([]auth.Authenticator, 0, len(cfg.Methods))
+	for _, method := range cfg.Methods {
+		var (
+			authenticator auth.Authenticator
+			err           error
+		)
+		switch method {
+		case authMethodAPIKey:
+			authenticator, err = newAPIKeyAuthenticator(cfg)
+		case authMethodJWT:
+			authenticator, err = newJWTAuthenticator(cfg.JWT)
+		}
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, authenticator)
+	}
+	return auth.Chain(authenticators...), nil
+}
+
+func newAPIKeyAuthenticator(cfg config.AuthConfig) (auth.Authenticator, error) {
+	keys := make([]auth.APIKey, 0, len(cfg.APIKeys))
+	for _, key := range cfg.APIKeys {
+		keys = append(keys, auth.APIKey{Name: key.Name, Hash: key.Hash, Scopes: key.Scopes})
+	}
+	if cfg.APIKeysFile != "" {
+		fileKeys, err := auth.LoadAPIKeys(cfg.APIKeysFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, fileKeys...)
+	}
+	return auth.NewAPIKeyAuthenticator(keys)
+}
+
+func newJWTAuthenticator(cfg config.JWTConfig) (auth.Authenticator, error) {
+	keys, err := auth.LoadJWKS(cfg.JWKSFile)
+	if err != nil {
+		return nil, err
+	}
+	return auth.NewJWTAuthenticator(keys, auth.JWTOptions{
+		Issuer:   cfg.Issuer,
+		Audience: cfg.Audience,
+		Leeway:   cfg.Leeway.Std(),
+	}), nil
+}
+
+// newAuthorizer returns the per-route scope check. Without authentication
+// there is no principal to check, so every route is left open.
+func newAuthorizer(cfg config.AuthConfig) func(scope string) gin.HandlerFunc {
+	if !cfg.Enabled {
+		return func(string) gin.HandlerFunc {
+			return func(ctx *gin.Context) { ctx.Next() }
+		}
+	}
+	return handler.RequireScope
+}
@@ -0,0 +1,14 @@
+package main
+
+import (
+	"educabot.com/bookshop/config"
+	"educabot.com/bookshop/handler"
+)
+
+func newCompressionPolicy(cfg config.CompressionConfig) handler.CompressionPolicy {
+	return handler.CompressionPolicy{
+		Encodings:            cfg.Encodings,
+		MinSize:              cfg.MinSize,
+		ExcludedContentTypes: cfg.ExcludedContentTypes,
+	}
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"educabot.com/bookshop/config"
+	"educabot.com/bookshop/handler"
+)
+
+func newCORSPolicy(cfg config.CORSConfig) handler.CORSPolicy {
+	return handler.CORSPolicy{
+		AllowedOrigins:   cfg.AllowedOrigins,
+		AllowedMethods:   cfg.AllowedMethods,
+		AllowedHeaders:   cfg.AllowedHeaders,
+		ExposedHeaders:   cfg.ExposedHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           cfg.MaxAge.Std(),
+	}
+}
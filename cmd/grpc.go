@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net"
+
+	"educabot.com/bookshop/auth"
+	"educabot.com/bookshop/config"
+	bookshopv1 "educabot.com/bookshop/proto/bookshop/v1"
+	"educabot.com/bookshop/ratelimit"
+	"educabot.com/bookshop/rpc"
+	"educabot.com/bookshop/server"
+	"educabot.com/bookshop/service"
+	"educabot.com/bookshop/telemetry"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+// grpcMiddleware is what the gRPC server needs to treat calls the way the
+// HTTP middleware treats requests. A nil authenticator or store turns that
+// step off.
+type grpcMiddleware struct {
+	logger         *slog.Logger
+	metrics        *telemetry.Metrics
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+	authenticator  auth.Authenticator
+	store          ratelimit.Store
+	rateLimit      config.RateLimitConfig
+}
+
+// newGRPCServer chains the interceptors in the same order as the HTTP
+// middleware: logging, metrics and tracing outermost so they see every
+// outcome, then panic recovery, then rate limiting around authentication,
+// which checks calls for the scope the HTTP API needs for the same reads.
+func newGRPCServer(metricsSvc service.MetricsService, booksSvc service.BooksService, mw grpcMiddleware) *grpc.Server {
+	unary := []grpc.UnaryServerInterceptor{
+		rpc.UnaryLogCalls(mw.logger),
+		rpc.UnaryInstrument(mw.metrics),
+		rpc.UnaryTrace(mw.tracerProvider, mw.propagator),
+		rpc.UnaryRecover(mw.logger, mw.metrics),
+	}
+	stream := []grpc.StreamServerInterceptor{
+		rpc.StreamLogCalls(mw.logger),
+		rpc.StreamInstrument(mw.metrics),
+		rpc.StreamTrace(mw.tracerProvider, mw.propagator),
+		rpc.StreamRecover(mw.logger, mw.metrics),
+	}
+	if mw.authenticator != nil {
+		if mw.store != nil {
+			ipLimit := newIPRateLimit(mw.rateLimit)
+			unary = append(unary, rpc.UnaryRateLimitByIP(mw.store, ipLimit))
+			stream = append(stream, rpc.StreamRateLimitByIP(mw.store, ipLimit))
+		}
+		unary = append(unary, rpc.UnaryAuthenticate(mw.authenticator, auth.ScopeBooksRead))
+		stream = append(stream, rpc.StreamAuthenticate(mw.authenticator, auth.ScopeBooksRead))
+	}
+	if mw.store != nil {
+		limit := newRateLimitRules(mw.rateLimit).Default
+		unary = append(unary, rpc.UnaryRateLimit(mw.store, limit))
+		stream = append(stream, rpc.StreamRateLimit(mw.store, limit))
+	}
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(unary...), grpc.ChainStreamInterceptor(stream...))
+	bookshopv1.RegisterBookMetricsServer(grpcServer, rpc.NewBookMetricsServer(metricsSvc, booksSvc))
+	return grpcServer
+}
+
+func startGRPC(grpcServer *grpc.Server, address string) (server.ShutdownHook, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		if err := grpcServer.Serve(listener); err != nil {
+			slog.Error("gRPC server stopped", "address", address, "error", err)
+		}
+	}()
+	return stopGRPC(grpcServer), nil
+}
+
+func stopGRPC(grpcServer *grpc.Server) server.ShutdownHook {
+	return func(ctx context.Context) error {
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+			return nil
+		case <-ctx.Done():
+			grpcServer.Stop()
+			return ctx.Err()
+		}
+	}
+}
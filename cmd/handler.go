@@ -1,10 +1,54 @@
 package main
 
 import (
+	"time"
+
+	"educabot.com/bookshop/config"
 	"educabot.com/bookshop/handler"
+	"educabot.com/bookshop/health"
 	"educabot.com/bookshop/service"
+	"github.com/gin-gonic/gin"
 )
 
 func newMetricsHandler(metricsSvc service.MetricsService) handler.MetricsHandler {
 	return handler.NewMetricsHandler(metricsSvc)
 }
+
+func newMetricsHandlerV2(metricsSvc service.MetricsService) handler.MetricsHandler {
+	return handler.NewMetricsHandlerV2(metricsSvc)
+}
+
+func newBooksHandler(booksSvc service.BooksService) handler.BooksHandler {
+	return handler.NewBooksHandler(booksSvc)
+}
+
+func newGraphQLHandler(metricsSvc service.MetricsService, booksSvc service.BooksService, cfg config.GraphQLConfig) (handler.GraphQLHandler, error) {
+	return handler.NewGraphQLHandler(metricsSvc, booksSvc, handler.GraphQLLimits{
+		MaxDepth:      cfg.MaxDepth,
+		MaxComplexity: cfg.MaxComplexity,
+		MaxQueryBytes: cfg.MaxQueryBytes,
+	})
+}
+
+func newStreamHandler(catalogWatcher service.CatalogWatcher) handler.StreamHandler {
+	return handler.NewStreamHandler(catalogWatcher)
+}
+
+func newSubscriptionsHandler(manager service.SubscriptionManager) handler.SubscriptionsHandler {
+	return handler.NewSubscriptionsHandler(manager)
+}
+
+func newAdminHandler(refresher service.CatalogRefresher) handler.AdminHandler {
+	return handler.NewAdminHandler(refresher)
+}
+
+func newHealthHandler(registry *health.Registry) handler.HealthHandler {
+	return handler.NewHealthHandler(registry, time.Now())
+}
+
+func newCacheable(catalogWatcher service.CatalogWatcher, cacheCfg config.HTTPCacheConfig, authCfg config.AuthConfig) gin.HandlerFunc {
+	return handler.Cacheable(catalogWatcher, handler.HTTPCachePolicy{
+		MaxAge:  cacheCfg.MaxAge.Std(),
+		Private: authCfg.Enabled,
+	})
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"educabot.com/bookshop/config"
+	"educabot.com/bookshop/health"
+	"educabot.com/bookshop/repository"
+	"educabot.com/bookshop/service"
+)
+
+const (
+	checkUpstream   = "upstream"
+	checkCatalog    = "catalog"
+	checkSnapshot   = "snapshot"
+	checkQuarantine = "quarantine"
+)
+
+func newHealthRegistry(cfg config.Config, bookRepo upstreamRepository, quarantines map[string]health.QuarantineReporter, catalogWatcher service.CatalogWatcher, snapshotRepo *repository.SnapshotBookRepository) *health.Registry {
+	registry := health.NewRegistry(cfg.Health.CheckTimeout.Std())
+	upstreamCheck := health.UpstreamCheck(bookRepo, bookRepo, cfg.Health.MaxFetchAge.Std())
+	if snapshotRepo != nil {
+		upstreamCheck = health.WithFallback(upstreamCheck, func() bool {
+			_, ok := snapshotRepo.Snapshot()
+			return ok
+		})
+		registry.Register(checkSnapshot, health.SnapshotCheck(snapshotRepo))
+	}
+	registry.Register(checkUpstream, upstreamCheck)
+	registry.Register(checkCatalog, health.WarmCheck(catalogWatcher))
+	if repository.ValidationPolicy(cfg.Upstream.ValidationPolicy) == repository.PolicyQuarantine {
+		registry.Register(checkQuarantine, health.QuarantineCheck(quarantines))
+	}
+	return registry
+}
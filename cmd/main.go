@@ -1,15 +1,179 @@
 package main
 
-import "github.com/gin-gonic/gin"
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"educabot.com/bookshop/auth"
+	"educabot.com/bookshop/config"
+	"educabot.com/bookshop/handler"
+	"educabot.com/bookshop/ratelimit"
+	"educabot.com/bookshop/repository"
+	"educabot.com/bookshop/telemetry"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+)
 
 func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run() error {
+	cfg, flags, err := config.Load(os.Args[1:], os.LookupEnv)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if flags.PrintConfig {
+		if err := config.Print(os.Stdout, cfg); err != nil {
+			return err
+		}
+		return cfg.Validate()
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	logger, err := newLogger(cfg.Logging)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	tracerProvider, err := newTracerProvider(ctx, cfg.Tracing)
+	if err != nil {
+		return err
+	}
+	propagator := telemetry.NewPropagator()
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagator)
+
+	metrics := telemetry.NewMetrics()
 	router := gin.New()
-	router.SetTrustedProxies(nil)
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return err
+	}
+	srv := newServer(cfg.Server, router)
+	srv.OnShutdown(tracerProvider.Shutdown)
+	router.Use(
+		handler.RequestID(),
+		handler.LogRequests(logger),
+		handler.Instrument(metrics),
+		handler.Trace(tracerProvider, propagator),
+	)
+	// Compression wraps recovery so the error written for a panic is flushed
+	// through the compressing writer rather than left in its buffer.
+	if cfg.Compression.Enabled {
+		router.Use(handler.Compress(newCompressionPolicy(cfg.Compression)))
+	}
+	router.Use(handler.Recover(logger, metrics), handler.CatalogSource())
+	if len(cfg.CORS.AllowedOrigins) > 0 {
+		router.Use(handler.CORS(newCORSPolicy(cfg.CORS)))
+	}
+	var store *ratelimit.MemoryStore
+	if cfg.RateLimit.Enabled {
+		store = ratelimit.NewMemoryStore()
+		srv.Go(func(ctx context.Context) { store.Run(ctx, cfg.RateLimit.CleanupInterval.Std()) })
+	}
+	var authenticator auth.Authenticator
+	if cfg.Auth.Enabled {
+		authenticator, err = newAuthenticator(cfg.Auth)
+		if err != nil {
+			return err
+		}
+		// Limiting by IP ahead of authentication makes failed attempts count,
+		// so credentials can't be brute-forced.
+		if store != nil {
+			router.Use(handler.RateLimitByIP(store, newIPRateLimit(cfg.RateLimit), cfg.RateLimit.ExemptPaths))
+		}
+		router.Use(handler.Authenticate(authenticator, cfg.Auth.ExemptPaths))
+	}
+	if store != nil {
+		router.Use(handler.RateLimit(store, newRateLimitRules(cfg.RateLimit)))
+	}
+
+	bookRepo, quarantines := newBookRepository(cfg.Upstream, tracerProvider, propagator, metrics)
+	// Upstream metrics are recorded beneath the snapshot so failures it covers
+	// for still show up.
+	servedRepo := telemetry.InstrumentBookRepository(bookRepo, metrics)
+	var snapshotRepo *repository.SnapshotBookRepository
+	if cfg.Snapshot.Enabled {
+		snapshotRepo = newSnapshotRepository(servedRepo, cfg.Snapshot)
+		servedRepo = snapshotRepo
+	}
+	instrumentedRepo := telemetry.TraceBookRepository(servedRepo, tracerProvider)
+	// With refreshing on, requests are served from the latest background
+	// refresh and never wait on the upstream.
+	var catalogRepo repository.BookRepository = instrumentedRepo
+	var adminHandler handler.AdminHandler
+	if cfg.Refresh.Enabled {
+		refresher := newCatalogRefresher(instrumentedRepo, cfg.Refresh)
+		seedCatalogRefresher(refresher, snapshotRepo)
+		srv.Go(refresher.Run)
+		catalogRepo = refresher
+		// Manual refreshes hit the upstream, so they are only offered to
+		// callers that can be checked for the admin scope.
+		if cfg.Auth.Enabled {
+			adminHandler = newAdminHandler(refresher)
+		}
+	}
+	metricsSvc := telemetry.TraceMetricsService(newMetricsService(catalogRepo), tracerProvider)
+	booksSvc := telemetry.TraceBooksService(newBooksService(catalogRepo), tracerProvider)
+	catalogWatcher := newCatalogWatcher(catalogRepo, cfg.Catalog)
+	seedCatalogWatcher(catalogWatcher, snapshotRepo)
+	subscriptionManager := newSubscriptionManager(metricsSvc, booksSvc, catalogWatcher, cfg.Subscriptions)
+	srv.Go(catalogWatcher.Run)
+	srv.Go(subscriptionManager.Run)
+
+	healthRegistry := newHealthRegistry(cfg, bookRepo, quarantines, catalogWatcher, snapshotRepo)
+
+	graphqlHandler, err := newGraphQLHandler(metricsSvc, booksSvc, cfg.GraphQL)
+	if err != nil {
+		return err
+	}
 
-	bookRepo := newBookRepository()
-	metricsSvc := newMetricsService(bookRepo)
-	metricsHandler := newMetricsHandler(metricsSvc)
+	grpcMW := grpcMiddleware{
+		logger:         logger,
+		metrics:        metrics,
+		tracerProvider: tracerProvider,
+		propagator:     propagator,
+		authenticator:  authenticator,
+		rateLimit:      cfg.RateLimit,
+	}
+	if store != nil {
+		grpcMW.store = store
+	}
+	stopGRPC, err := startGRPC(newGRPCServer(metricsSvc, booksSvc, grpcMW), cfg.GRPC.Address)
+	if err != nil {
+		return err
+	}
+	srv.OnShutdown(stopGRPC)
 
-	setupRoutes(router, metricsHandler)
-	router.Run(":3000")
+	setupRoutes(router, handlers{
+		metrics:       newMetricsHandler(metricsSvc),
+		metricsV2:     newMetricsHandlerV2(metricsSvc),
+		books:         newBooksHandler(booksSvc),
+		graphql:       graphqlHandler,
+		stream:        newStreamHandler(catalogWatcher),
+		subscriptions: newSubscriptionsHandler(subscriptionManager),
+		health:        newHealthHandler(healthRegistry),
+		admin:         adminHandler,
+		prometheus:    metrics.Handler(),
+		authorize:     newAuthorizer(cfg.Auth),
+		cacheable:     newCacheable(catalogWatcher, cfg.HTTPCache, cfg.Auth),
+	})
+	return srv.Run(ctx)
 }
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"educabot.com/bookshop/config"
+	"educabot.com/bookshop/handler"
+	"educabot.com/bookshop/ratelimit"
+)
+
+func newRateLimitRules(cfg config.RateLimitConfig) handler.RateLimitRules {
+	routes := make(map[string]ratelimit.Limit, len(cfg.Routes))
+	for _, route := range cfg.Routes {
+		routes[route.Route] = ratelimit.Limit{Rate: route.RequestsPerSecond, Burst: route.Burst}
+	}
+	return handler.RateLimitRules{
+		Default:     ratelimit.Limit{Rate: cfg.RequestsPerSecond, Burst: cfg.Burst},
+		Routes:      routes,
+		ExemptPaths: cfg.ExemptPaths,
+	}
+}
+
+func newIPRateLimit(cfg config.RateLimitConfig) ratelimit.Limit {
+	return ratelimit.Limit{Rate: cfg.IPRequestsPerSecond, Burst: cfg.IPBurst}
+}
@@ -3,9 +3,93 @@ package main
 import (
 	"net/http"
 
+	"educabot.com/bookshop/config"
+	"educabot.com/bookshop/health"
 	"educabot.com/bookshop/repository"
+	"educabot.com/bookshop/telemetry"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func newBookRepository() repository.BookRepository {
-	return repository.NewHTTPBookRepository(&http.Client{})
+type (
+	repositoryObserver interface {
+		repository.CacheObserver
+		repository.ValidationObserver
+	}
+
+	upstreamRepository interface {
+		repository.BookRepository
+		health.FetchStatusReporter
+	}
+
+	quarantiningRepository interface {
+		repository.BookRepository
+		health.QuarantineReporter
+	}
+)
+
+// newBookRepository also returns every provider that can hold books in
+// quarantine, by name, so readiness can report on them.
+func newBookRepository(cfg config.UpstreamConfig, tracerProvider trace.TracerProvider, propagator propagation.TextMapPropagator, observer repositoryObserver) (upstreamRepository, map[string]health.QuarantineReporter) {
+	client := &http.Client{
+		Timeout:   cfg.Timeout.Std(),
+		Transport: telemetry.TracedTransport(http.DefaultTransport, tracerProvider, propagator),
+	}
+	quarantines := make(map[string]health.QuarantineReporter)
+	primary := newPrimaryRepository(client, cfg, observer, quarantines)
+	if len(cfg.Fallbacks) == 0 {
+		return primary, quarantines
+	}
+
+	chain := []repository.Provider{{Name: config.PrimaryProviderName, Priority: 0, Repository: primary}}
+	for i, fallback := range cfg.Fallbacks {
+		var repo quarantiningRepository
+		if fallback.File != "" {
+			repo = repository.NewFileBookRepository(fallback.File).
+				WithValidationObserver(observer).
+				WithDecodeOptions(newDecodeOptions(cfg))
+		} else {
+			repo = newHTTPBookRepository(client, fallback.URL, cfg, observer)
+		}
+		quarantines[fallback.Name] = repo
+		chain = append(chain, repository.Provider{Name: fallback.Name, Priority: i + 1, Repository: repo})
+	}
+	return repository.NewFailoverBookRepository(chain, cfg.FailoverCooldown.Std()), quarantines
+}
+
+func newPrimaryRepository(client *http.Client, cfg config.UpstreamConfig, observer repositoryObserver, quarantines map[string]health.QuarantineReporter) upstreamRepository {
+	if len(cfg.Providers) == 0 {
+		repo := newHTTPBookRepository(client, cfg.URL, cfg, observer)
+		quarantines[config.PrimaryProviderName] = repo
+		return repo
+	}
+
+	providers := make([]repository.Provider, 0, len(cfg.Providers))
+	for _, provider := range cfg.Providers {
+		repo := newHTTPBookRepository(client, provider.URL, cfg, observer)
+		quarantines[provider.Name] = repo
+		providers = append(providers, repository.Provider{
+			Name:       provider.Name,
+			Priority:   provider.Priority,
+			Repository: repo,
+		})
+	}
+	return repository.NewMultiBookRepository(providers,
+		repository.MergeKey(cfg.MergeKey), repository.ConflictPolicy(cfg.ConflictPolicy))
+}
+
+func newHTTPBookRepository(client *http.Client, url string, cfg config.UpstreamConfig, observer repositoryObserver) *repository.HTTPBookRepository {
+	return repository.NewHTTPBookRepository(client, url).
+		WithCacheObserver(observer).
+		WithValidationObserver(observer).
+		WithDecodeOptions(newDecodeOptions(cfg))
+}
+
+func newDecodeOptions(cfg config.UpstreamConfig) repository.DecodeOptions {
+	return repository.DecodeOptions{
+		MaxBodyBytes: cfg.MaxBodyBytes,
+		Policy:       repository.ValidationPolicy(cfg.ValidationPolicy),
+		Mode:         repository.DecodingMode(cfg.DecodingMode),
+		Rounding:     repository.RoundingPolicy(cfg.Rounding),
+	}
 }
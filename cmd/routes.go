@@ -1,15 +1,79 @@
 package main
 
 import (
+	"net/http"
+	"time"
+
+	"educabot.com/bookshop/auth"
 	"educabot.com/bookshop/handler"
 	"github.com/gin-gonic/gin"
 )
 
-func setupRoutes(router *gin.Engine, metricsHandler handler.MetricsHandler) {
-	books := router.Group("/books")
+const (
+	v2BooksPath       = "/v2/books"
+	graphqlPath       = "/graphql"
+	streamPath        = "/stream"
+	subscriptionsPath = "/subscriptions"
+	livenessPath      = "/healthz"
+	readinessPath     = "/readyz"
+	prometheusPath    = "/metrics"
+	refreshPath       = "/admin/catalog/refresh"
+)
+
+var (
+	v1Deprecated = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	v1Sunset     = time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC)
+)
+
+type handlers struct {
+	metrics       handler.MetricsHandler
+	metricsV2     handler.MetricsHandler
+	books         handler.BooksHandler
+	graphql       handler.GraphQLHandler
+	stream        handler.StreamHandler
+	subscriptions handler.SubscriptionsHandler
+	health        handler.HealthHandler
+	admin         handler.AdminHandler
+	prometheus    http.Handler
+	authorize     func(scope string) gin.HandlerFunc
+	cacheable     gin.HandlerFunc
+}
+
+func setupRoutes(router *gin.Engine, h handlers) {
+	router.GET(livenessPath, h.health.Liveness)
+	router.GET(readinessPath, h.health.Readiness)
+	router.GET(prometheusPath, gin.WrapH(h.prometheus))
+	if h.admin != nil {
+		router.POST(refreshPath, h.authorize(auth.ScopeAdmin), h.admin.RefreshCatalog)
+	}
+
+	readBooks := h.authorize(auth.ScopeBooksRead)
+	deprecated := handler.Deprecated(v1Deprecated, v1Sunset, v2BooksPath)
+	unversioned := router.Group("/books", readBooks, deprecated)
 	{
-		books.GET("/mean-units-sold", metricsHandler.GetMeanUnitsSold)
-		books.GET("/cheapest", metricsHandler.GetCheapestBook)
-		books.GET("/count-by-author/:author", metricsHandler.GetBooksCountByAuthor)
+		registerMetricsRoutes(unversioned, h.metrics, h.cacheable)
+		unversioned.GET(streamPath, h.stream.StreamCatalog)
+		unversioned.GET(subscriptionsPath, h.subscriptions.Subscribe)
 	}
-}
\ No newline at end of file
+	registerMetricsRoutes(router.Group("/v1/books", readBooks, deprecated), h.metrics, h.cacheable)
+
+	v2 := router.Group(v2BooksPath, readBooks)
+	{
+		v2.GET("", h.cacheable, h.books.ListBooks)
+		v2.GET(streamPath, h.stream.StreamCatalog)
+		v2.GET(subscriptionsPath, h.subscriptions.Subscribe)
+		registerMetricsRoutes(v2, h.metricsV2, h.cacheable)
+	}
+
+	graphql := router.Group(graphqlPath, readBooks)
+	{
+		graphql.GET("", h.graphql.Serve)
+		graphql.POST("", h.graphql.Serve)
+	}
+}
+
+func registerMetricsRoutes(group *gin.RouterGroup, metricsHandler handler.MetricsHandler, cacheable gin.HandlerFunc) {
+	group.GET("/mean-units-sold", cacheable, metricsHandler.GetMeanUnitsSold)
+	group.GET("/cheapest", cacheable, metricsHandler.GetCheapestBook)
+	group.GET("/count-by-author/:author", cacheable, metricsHandler.GetBooksCountByAuthor)
+}
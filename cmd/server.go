@@ -0,0 +1,20 @@
+package main
+
+import (
+	"net/http"
+
+	"educabot.com/bookshop/config"
+	"educabot.com/bookshop/server"
+)
+
+func newServer(cfg config.ServerConfig, handler http.Handler) *server.Server {
+	return server.New(server.Config{
+		Address:           cfg.Address,
+		ReadTimeout:       cfg.ReadTimeout.Std(),
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout.Std(),
+		WriteTimeout:      cfg.WriteTimeout.Std(),
+		IdleTimeout:       cfg.IdleTimeout.Std(),
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+		ShutdownTimeout:   cfg.ShutdownTimeout.Std(),
+	}, handler)
+}
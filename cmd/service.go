@@ -1,6 +1,7 @@
 package main
 
 import (
+	"educabot.com/bookshop/config"
 	"educabot.com/bookshop/repository"
 	"educabot.com/bookshop/service"
 )
@@ -8,3 +9,22 @@ import (
 func newMetricsService(bookRepo repository.BookRepository) service.MetricsService {
 	return service.NewMetricsService(bookRepo)
 }
+
+func newBooksService(bookRepo repository.BookRepository) service.BooksService {
+	return service.NewBooksService(bookRepo)
+}
+
+func newCatalogWatcher(bookRepo repository.BookRepository, cfg config.CatalogConfig) service.CatalogWatcher {
+	return service.NewCatalogWatcher(bookRepo, cfg.PollInterval.Std())
+}
+
+func newCatalogRefresher(bookRepo repository.BookRepository, cfg config.RefreshConfig) service.CatalogRefresher {
+	return service.NewCatalogRefresher(bookRepo, service.RefreshSchedule{
+		Interval: cfg.Interval.Std(),
+		Jitter:   cfg.Jitter.Std(),
+	})
+}
+
+func newSubscriptionManager(metricsSvc service.MetricsService, booksSvc service.BooksService, catalogWatcher service.CatalogWatcher, cfg config.SubscriptionsConfig) service.SubscriptionManager {
+	return service.NewSubscriptionManager(metricsSvc, booksSvc, catalogWatcher, cfg.MaxPerSession)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"log/slog"
+
+	"educabot.com/bookshop/config"
+	"educabot.com/bookshop/repository"
+	"educabot.com/bookshop/service"
+)
+
+// newSnapshotRepository loads the snapshot left by a previous run and seeds
+// the watcher with it. An unreadable snapshot is logged and otherwise ignored;
+// the next successful fetch replaces it.
+func newSnapshotRepository(bookRepo repository.BookRepository, cfg config.SnapshotConfig) *repository.SnapshotBookRepository {
+	repo := repository.NewSnapshotBookRepository(bookRepo, repository.NewSnapshotStore(cfg.Path))
+	snapshot, ok, err := repo.Load()
+	switch {
+	case err != nil:
+		slog.Warn("ignoring catalog snapshot", "path", cfg.Path, "error", err)
+	case ok:
+		slog.Info("loaded catalog snapshot",
+			"path", cfg.Path, "saved_at", snapshot.SavedAt, "source", snapshot.Source, "books", len(snapshot.Books))
+	}
+	return repo
+}
+
+// seedCatalogRefresher serves the persisted catalog until the first refresh
+// completes, naming it as the snapshot since it may be stale.
+func seedCatalogRefresher(refresher service.CatalogRefresher, snapshotRepo *repository.SnapshotBookRepository) {
+	if snapshotRepo == nil {
+		return
+	}
+	if snapshot, ok := snapshotRepo.Snapshot(); ok {
+		refresher.Seed(snapshot.Books, repository.SnapshotSource, snapshot.SavedAt)
+	}
+}
+
+func seedCatalogWatcher(catalogWatcher service.CatalogWatcher, snapshotRepo *repository.SnapshotBookRepository) {
+	if snapshotRepo == nil {
+		return
+	}
+	if snapshot, ok := snapshotRepo.Snapshot(); ok {
+		catalogWatcher.Seed(snapshot.Books, snapshot.SavedAt)
+	}
+}
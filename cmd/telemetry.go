@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+
+	"educabot.com/bookshop/config"
+	"educabot.com/bookshop/logging"
+	"educabot.com/bookshop/telemetry"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func newTracerProvider(ctx context.Context, cfg config.TracingConfig) (*sdktrace.TracerProvider, error) {
+	return telemetry.NewTracerProvider(ctx, telemetry.TracingConfig{
+		Exporter:     cfg.Exporter,
+		OTLPEndpoint: cfg.OTLPEndpoint,
+		OTLPInsecure: cfg.OTLPInsecure,
+		ServiceName:  cfg.ServiceName,
+		SampleRatio:  cfg.SampleRatio,
+	}, os.Stdout)
+}
+
+func newLogger(cfg config.LoggingConfig) (*slog.Logger, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		return nil, err
+	}
+	return logging.NewLogger(os.Stdout, logging.Config{Level: level, SampleEvery: cfg.SampleEvery}), nil
+}
@@ -0,0 +1,314 @@
+package config
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type (
+	Duration time.Duration
+
+	Config struct {
+		Server        ServerConfig        `json:"server"`
+		GRPC          GRPCConfig          `json:"grpc"`
+		Upstream      UpstreamConfig      `json:"upstream"`
+		Catalog       CatalogConfig       `json:"catalog"`
+		GraphQL       GraphQLConfig       `json:"graphql"`
+		Subscriptions SubscriptionsConfig `json:"subscriptions"`
+		Health        HealthConfig        `json:"health"`
+		Tracing       TracingConfig       `json:"tracing"`
+		Logging       LoggingConfig       `json:"logging"`
+		Auth          AuthConfig          `json:"auth"`
+		RateLimit     RateLimitConfig     `json:"rate_limit"`
+		CORS          CORSConfig          `json:"cors"`
+		HTTPCache     HTTPCacheConfig     `json:"http_cache"`
+		Compression   CompressionConfig   `json:"compression"`
+		Snapshot      SnapshotConfig      `json:"snapshot"`
+		Refresh       RefreshConfig       `json:"refresh"`
+	}
+
+	ServerConfig struct {
+		Address           string   `json:"address"`
+		ReadTimeout       Duration `json:"read_timeout"`
+		ReadHeaderTimeout Duration `json:"read_header_timeout"`
+		WriteTimeout      Duration `json:"write_timeout"`
+		IdleTimeout       Duration `json:"idle_timeout"`
+		MaxHeaderBytes    int      `json:"max_header_bytes"`
+		ShutdownTimeout   Duration `json:"shutdown_timeout"`
+		TrustedProxies    []string `json:"trusted_proxies"`
+	}
+
+	GRPCConfig struct {
+		Address string `json:"address"`
+	}
+
+	UpstreamConfig struct {
+		URL              string             `json:"url" secret:"userinfo"`
+		Timeout          Duration           `json:"timeout"`
+		MaxBodyBytes     int64              `json:"max_body_bytes"`
+		ValidationPolicy string             `json:"validation_policy"`
+		DecodingMode     string             `json:"decoding_mode"`
+		Rounding         string             `json:"rounding"`
+		Providers        []UpstreamProvider `json:"providers"`
+		MergeKey         string             `json:"merge_key"`
+		ConflictPolicy   string             `json:"conflict_policy"`
+		Fallbacks        []UpstreamFallback `json:"fallbacks"`
+		FailoverCooldown Duration           `json:"failover_cooldown"`
+	}
+
+	CatalogConfig struct {
+		PollInterval Duration `json:"poll_interval"`
+	}
+
+	GraphQLConfig struct {
+		MaxDepth      int   `json:"max_depth"`
+		MaxComplexity int   `json:"max_complexity"`
+		MaxQueryBytes int64 `json:"max_query_bytes"`
+	}
+
+	SubscriptionsConfig struct {
+		MaxPerSession int `json:"max_per_session"`
+	}
+
+	HealthConfig struct {
+		CheckTimeout Duration `json:"check_timeout"`
+		MaxFetchAge  Duration `json:"max_fetch_age"`
+	}
+
+	TracingConfig struct {
+		Exporter     string  `json:"exporter"`
+		OTLPEndpoint string  `json:"otlp_endpoint"`
+		OTLPInsecure bool    `json:"otlp_insecure"`
+		ServiceName  string  `json:"service_name"`
+		SampleRatio  float64 `json:"sample_ratio"`
+	}
+
+	LoggingConfig struct {
+		Level       string `json:"level"`
+		SampleEvery int    `json:"sample_every"`
+	}
+
+	AuthConfig struct {
+		Enabled     bool           `json:"enabled"`
+		Methods     []string       `json:"methods"`
+		APIKeys     []APIKeyConfig `json:"api_keys"`
+		APIKeysFile string         `json:"api_keys_file"`
+		ExemptPaths []string       `json:"exempt_paths"`
+		JWT         JWTConfig      `json:"jwt"`
+	}
+
+	JWTConfig struct {
+		JWKSFile string   `json:"jwks_file"`
+		Issuer   string   `json:"issuer"`
+		Audience string   `json:"audience"`
+		Leeway   Duration `json:"leeway"`
+	}
+
+	RateLimitConfig struct {
+		Enabled           bool             `json:"enabled"`
+		RequestsPerSecond float64          `json:"requests_per_second"`
+		Burst             int              `json:"burst"`
+		Routes            []RouteRateLimit `json:"routes"`
+		ExemptPaths       []string         `json:"exempt_paths"`
+		CleanupInterval   Duration         `json:"cleanup_interval"`
+		// IPRequestsPerSecond and IPBurst limit each IP before authentication,
+		// bounding how fast credentials can be guessed when auth is enabled.
+		IPRequestsPerSecond float64 `json:"ip_requests_per_second"`
+		IPBurst             int     `json:"ip_burst"`
+	}
+
+	CORSConfig struct {
+		AllowedOrigins   []string `json:"allowed_origins"`
+		AllowedMethods   []string `json:"allowed_methods"`
+		AllowedHeaders   []string `json:"allowed_headers"`
+		ExposedHeaders   []string `json:"exposed_headers"`
+		AllowCredentials bool     `json:"allow_credentials"`
+		MaxAge           Duration `json:"max_age"`
+	}
+
+	HTTPCacheConfig struct {
+		MaxAge Duration `json:"max_age"`
+	}
+
+	CompressionConfig struct {
+		Enabled              bool     `json:"enabled"`
+		MinSize              int      `json:"min_size"`
+		Encodings            []string `json:"encodings"`
+		ExcludedContentTypes []string `json:"excluded_content_types"`
+	}
+
+	SnapshotConfig struct {
+		Enabled bool   `json:"enabled"`
+		Path    string `json:"path"`
+	}
+
+	// RefreshConfig moves catalog fetches off the request path: a background
+	// worker refreshes the catalog every Interval, give or take Jitter, and
+	// requests are served from the latest refresh.
+	RefreshConfig struct {
+		Enabled  bool     `json:"enabled"`
+		Interval Duration `json:"interval"`
+		Jitter   Duration `json:"jitter"`
+	}
+
+	// UpstreamProvider is one of several catalog sources. When any are set they
+	// replace upstream.url, and their books are merged.
+	UpstreamProvider struct {
+		Name     string `json:"name"`
+		URL      string `json:"url" secret:"userinfo"`
+		Priority int    `json:"priority"`
+	}
+
+	// UpstreamFallback serves books, in order, while the upstream is failing.
+	// Exactly one of URL or File is set; File holds a catalog in the upstream's
+	// JSON format.
+	UpstreamFallback struct {
+		Name string `json:"name"`
+		URL  string `json:"url" secret:"userinfo"`
+		File string `json:"file"`
+	}
+
+	RouteRateLimit struct {
+		Route             string  `json:"route"`
+		RequestsPerSecond float64 `json:"requests_per_second"`
+		Burst             int     `json:"burst"`
+	}
+
+	APIKeyConfig struct {
+		Name   string   `json:"name"`
+		Hash   string   `json:"hash" secret:"true"`
+		Scopes []string `json:"scopes"`
+	}
+)
+
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Address:           ":3000",
+			ReadTimeout:       Duration(15 * time.Second),
+			ReadHeaderTimeout: Duration(5 * time.Second),
+			WriteTimeout:      Duration(30 * time.Second),
+			IdleTimeout:       Duration(120 * time.Second),
+			MaxHeaderBytes:    1 << 20,
+			ShutdownTimeout:   Duration(20 * time.Second),
+		},
+		GRPC: GRPCConfig{
+			Address: ":3001",
+		},
+		Upstream: UpstreamConfig{
+			URL:              "https://6781684b85151f714b0aa5db.mockapi.io/api/v1/books",
+			Timeout:          Duration(10 * time.Second),
+			MaxBodyBytes:     10 << 20,
+			ValidationPolicy: "skip",
+			DecodingMode:     "lenient",
+			Rounding:         "nearest",
+			MergeKey:         "id",
+			ConflictPolicy:   "priority",
+			FailoverCooldown: Duration(30 * time.Second),
+		},
+		Catalog: CatalogConfig{
+			PollInterval: Duration(10 * time.Second),
+		},
+		GraphQL: GraphQLConfig{
+			MaxDepth:      6,
+			MaxComplexity: 100,
+			MaxQueryBytes: 64 << 10,
+		},
+		Subscriptions: SubscriptionsConfig{
+			MaxPerSession: 50,
+		},
+		Health: HealthConfig{
+			CheckTimeout: Duration(2 * time.Second),
+			MaxFetchAge:  Duration(time.Minute),
+		},
+		Tracing: TracingConfig{
+			Exporter:     "none",
+			OTLPEndpoint: "localhost:4317",
+			ServiceName:  "bookshop",
+			SampleRatio:  1,
+		},
+		Logging: LoggingConfig{
+			Level:       "info",
+			SampleEvery: 1,
+		},
+		Auth: AuthConfig{
+			Methods:     []string{"api_key"},
+			ExemptPaths: []string{"/healthz", "/readyz", "/metrics"},
+			JWT: JWTConfig{
+				Leeway: Duration(30 * time.Second),
+			},
+		},
+		RateLimit: RateLimitConfig{
+			RequestsPerSecond:   10,
+			Burst:               20,
+			ExemptPaths:         []string{"/healthz", "/readyz", "/metrics"},
+			CleanupInterval:     Duration(time.Minute),
+			IPRequestsPerSecond: 50,
+			IPBurst:             100,
+		},
+		CORS: CORSConfig{
+			AllowedMethods: []string{"GET", "POST"},
+			AllowedHeaders: []string{"Authorization", "Content-Type", "X-API-Key", "X-Request-ID"},
+			ExposedHeaders: []string{
+				"X-Request-ID", "Deprecation", "Sunset", "Link", "Retry-After",
+				"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "ETag", "Last-Modified",
+				"X-Catalog-Source",
+			},
+			MaxAge: Duration(10 * time.Minute),
+		},
+		HTTPCache: HTTPCacheConfig{
+			MaxAge: Duration(10 * time.Second),
+		},
+		Compression: CompressionConfig{
+			Enabled:   true,
+			MinSize:   1024,
+			Encodings: []string{"br", "zstd", "gzip"},
+			ExcludedContentTypes: []string{
+				"image/*", "video/*", "audio/*", "font/woff", "font/woff2", "text/event-stream",
+				"application/zip", "application/gzip", "application/zstd", "application/x-brotli",
+			},
+		},
+		Snapshot: SnapshotConfig{
+			Path: "catalog-snapshot.json",
+		},
+		Refresh: RefreshConfig{
+			Enabled:  true,
+			Interval: Duration(30 * time.Second),
+			Jitter:   Duration(5 * time.Second),
+		},
+	}
+}
+
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var nanoseconds int64
+	if err := json.Unmarshal(data, &nanoseconds); err == nil {
+		*d = Duration(nanoseconds)
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	return d.UnmarshalText([]byte(text))
+}
@@ -0,0 +1,11 @@
+package config
+
+import "errors"
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported config file format")
+	ErrReadingFile       = errors.New("reading config file")
+	ErrDecodingFile      = errors.New("decoding config file")
+	ErrInvalidValue      = errors.New("invalid config value")
+	ErrInvalidConfig     = errors.New("invalid config")
+)
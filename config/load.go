@@ -0,0 +1,192 @@
+package config
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	flagSetName     = "bookshop"
+	flagConfigFile  = "config"
+	flagPrintConfig = "print-config"
+	envPrefix       = "BOOKSHOP_"
+	envConfigFile   = envPrefix + "CONFIG"
+	listSeparator   = ","
+)
+
+type (
+	Flags struct {
+		ConfigFile  string
+		PrintConfig bool
+	}
+
+	field struct {
+		key   string
+		value reflect.Value
+	}
+)
+
+func Load(args []string, lookupEnv func(string) (string, bool)) (Config, Flags, error) {
+	cfg := Default()
+	fields := collectFields(&cfg)
+
+	var flags Flags
+	overrides := make(map[string]string)
+	fs := flag.NewFlagSet(flagSetName, flag.ContinueOnError)
+	fs.StringVar(&flags.ConfigFile, flagConfigFile, "", "path to a YAML, JSON or TOML config file (env "+envConfigFile+")")
+	fs.BoolVar(&flags.PrintConfig, flagPrintConfig, false, "print the effective configuration with secrets redacted and exit")
+	for _, f := range fields {
+		key := f.key
+		fs.Func(flagName(key), fmt.Sprintf("%s (env %s)", key, envName(key)), func(value string) error {
+			overrides[key] = value
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return Config{}, flags, err
+	}
+
+	if flags.ConfigFile == "" {
+		flags.ConfigFile, _ = lookupEnv(envConfigFile)
+	}
+	if flags.ConfigFile != "" {
+		if err := loadFile(flags.ConfigFile, &cfg); err != nil {
+			return Config{}, flags, err
+		}
+	}
+	for _, f := range fields {
+		if value, ok := lookupEnv(envName(f.key)); ok {
+			if err := setField(f, value); err != nil {
+				return Config{}, flags, fmt.Errorf("%w: %s: %w", ErrInvalidValue, envName(f.key), err)
+			}
+		}
+	}
+	for _, f := range fields {
+		if value, ok := overrides[f.key]; ok {
+			if err := setField(f, value); err != nil {
+				return Config{}, flags, fmt.Errorf("%w: --%s: %w", ErrInvalidValue, flagName(f.key), err)
+			}
+		}
+	}
+	return cfg, flags, nil
+}
+
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrReadingFile, err)
+	}
+
+	var raw map[string]interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &raw)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, path)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrDecodingFile, err)
+	}
+
+	normalized, err := json.Marshal(raw)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrDecodingFile, err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(normalized))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(cfg); err != nil {
+		return fmt.Errorf("%w: %w", ErrDecodingFile, err)
+	}
+	return nil
+}
+
+func collectFields(cfg *Config) []field {
+	return collectStructFields(reflect.ValueOf(cfg).Elem(), "")
+}
+
+func collectStructFields(value reflect.Value, prefix string) []field {
+	var fields []field
+	for i := 0; i < value.NumField(); i++ {
+		structField := value.Type().Field(i)
+		name, _, _ := strings.Cut(structField.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		key := prefix + name
+		fieldValue := value.Field(i)
+		if fieldValue.Kind() == reflect.Struct {
+			fields = append(fields, collectStructFields(fieldValue, key+".")...)
+			continue
+		}
+		fields = append(fields, field{key: key, value: fieldValue})
+	}
+	return fields
+}
+
+func setField(f field, raw string) error {
+	if unmarshaler, ok := f.value.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return unmarshaler.UnmarshalText([]byte(raw))
+	}
+	switch f.value.Kind() {
+	case reflect.String:
+		f.value.SetString(raw)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		f.value.SetBool(parsed)
+	case reflect.Int, reflect.Int64:
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		f.value.SetInt(parsed)
+	case reflect.Float64:
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		f.value.SetFloat(parsed)
+	case reflect.Slice:
+		if f.value.Type().Elem().Kind() == reflect.Struct {
+			return json.Unmarshal([]byte(raw), f.value.Addr().Interface())
+		}
+		if f.value.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported list type %s", f.value.Type())
+		}
+		var items []string
+		for _, item := range strings.Split(raw, listSeparator) {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		f.value.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported type %s", f.value.Type())
+	}
+	return nil
+}
+
+func envName(key string) string {
+	return envPrefix + strings.ToUpper(strings.NewReplacer(".", "_").Replace(key))
+}
+
+func flagName(key string) string {
+	return strings.ReplaceAll(key, "_", "-")
+}
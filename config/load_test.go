@@ -0,0 +1,178 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	fileAddress  = ":4000"
+	envAddress   = ":5000"
+	flagAddress  = ":6000"
+	fileTimeout  = "3s"
+	fileMaxDepth = 9
+
+	yamlConfig = `
+server:
+  address: ":4000"
+  read_timeout: 3s
+graphql:
+  max_depth: 9
+`
+	jsonConfig = `{"server": {"address": ":4000", "read_timeout": "3s"}, "graphql": {"max_depth": 9}}`
+	tomlConfig = `
+[server]
+address = ":4000"
+read_timeout = "3s"
+
+[graphql]
+max_depth = 9
+`
+	unknownFieldConfig = `{"server": {"port": 4000}}`
+	malformedConfig    = `server: [`
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func envFrom(values map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	}
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, flags, err := Load(nil, envFrom(nil))
+
+	require.NoError(t, err)
+	require.Equal(t, Default(), cfg)
+	require.False(t, flags.PrintConfig)
+}
+
+func TestLoad_FileFormats(t *testing.T) {
+	files := map[string]string{
+		"config.yaml": yamlConfig,
+		"config.yml":  yamlConfig,
+		"config.json": jsonConfig,
+		"config.toml": tomlConfig,
+	}
+	expectedTimeout, err := time.ParseDuration(fileTimeout)
+	require.NoError(t, err)
+
+	for name, content := range files {
+		path := writeConfigFile(t, name, content)
+
+		cfg, _, err := Load([]string{"--config", path}, envFrom(nil))
+
+		require.NoError(t, err, name)
+		require.Equal(t, fileAddress, cfg.Server.Address, name)
+		require.Equal(t, expectedTimeout, cfg.Server.ReadTimeout.Std(), name)
+		require.Equal(t, fileMaxDepth, cfg.GraphQL.MaxDepth, name)
+		require.Equal(t, Default().GRPC, cfg.GRPC, name)
+	}
+}
+
+func TestLoad_ConfigFileFromEnv(t *testing.T) {
+	path := writeConfigFile(t, "config.json", jsonConfig)
+
+	cfg, flags, err := Load(nil, envFrom(map[string]string{envConfigFile: path}))
+
+	require.NoError(t, err)
+	require.Equal(t, path, flags.ConfigFile)
+	require.Equal(t, fileAddress, cfg.Server.Address)
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", yamlConfig)
+	env := envFrom(map[string]string{
+		"BOOKSHOP_SERVER_ADDRESS":    envAddress,
+		"BOOKSHOP_GRAPHQL_MAX_DEPTH": "11",
+	})
+
+	cfg, _, err := Load([]string{"--config", path, "--server.address", flagAddress}, env)
+
+	require.NoError(t, err)
+	require.Equal(t, flagAddress, cfg.Server.Address)
+	require.Equal(t, 11, cfg.GraphQL.MaxDepth)
+}
+
+func TestLoad_PrintConfigFlag(t *testing.T) {
+	_, flags, err := Load([]string{"--print-config"}, envFrom(nil))
+
+	require.NoError(t, err)
+	require.True(t, flags.PrintConfig)
+}
+
+func TestLoad_InvalidEnvValue(t *testing.T) {
+	_, _, err := Load(nil, envFrom(map[string]string{"BOOKSHOP_UPSTREAM_TIMEOUT": "soon"}))
+
+	require.ErrorIs(t, err, ErrInvalidValue)
+}
+
+func TestLoad_InvalidFlagValue(t *testing.T) {
+	_, _, err := Load([]string{"--graphql.max-depth", "deep"}, envFrom(nil))
+
+	require.ErrorIs(t, err, ErrInvalidValue)
+}
+
+func TestLoad_UnknownFlag(t *testing.T) {
+	_, _, err := Load([]string{"--port", "3000"}, envFrom(nil))
+
+	require.Error(t, err)
+}
+
+func TestLoad_Help(t *testing.T) {
+	_, _, err := Load([]string{"-h"}, envFrom(nil))
+
+	require.ErrorIs(t, err, flag.ErrHelp)
+}
+
+func TestLoad_UnsupportedFormat(t *testing.T) {
+	path := writeConfigFile(t, "config.ini", jsonConfig)
+
+	_, _, err := Load([]string{"--config", path}, envFrom(nil))
+
+	require.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	_, _, err := Load([]string{"--config", filepath.Join(t.TempDir(), "missing.yaml")}, envFrom(nil))
+
+	require.ErrorIs(t, err, ErrReadingFile)
+}
+
+func TestLoad_MalformedFile(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", malformedConfig)
+
+	_, _, err := Load([]string{"--config", path}, envFrom(nil))
+
+	require.ErrorIs(t, err, ErrDecodingFile)
+}
+
+func TestLoad_UnknownFieldInFile(t *testing.T) {
+	path := writeConfigFile(t, "config.json", unknownFieldConfig)
+
+	_, _, err := Load([]string{"--config", path}, envFrom(nil))
+
+	require.ErrorIs(t, err, ErrDecodingFile)
+}
+
+func TestLoad_StructListFromEnv(t *testing.T) {
+	env := envFrom(map[string]string{
+		"BOOKSHOP_AUTH_API_KEYS": `[{"name": "reporting", "hash": "sha256:abc", "scopes": ["books:read"]}]`,
+	})
+
+	cfg, _, err := Load(nil, env)
+
+	require.NoError(t, err)
+	require.Equal(t, []APIKeyConfig{{Name: "reporting", Hash: "sha256:abc", Scopes: []string{"books:read"}}}, cfg.Auth.APIKeys)
+}
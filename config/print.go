@@ -0,0 +1,79 @@
+package config
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"reflect"
+)
+
+const (
+	redactedValue = "REDACTED"
+
+	// Fields tagged secret:"true" are masked whole. Fields tagged
+	// secret:"userinfo" hold URLs and only lose their embedded credentials.
+	secretTag      = "secret"
+	secretUserinfo = "userinfo"
+)
+
+func Print(w io.Writer, cfg Config) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(Redact(cfg))
+}
+
+func Redact(cfg Config) Config {
+	redacted := cfg
+	redactStruct(reflect.ValueOf(&redacted).Elem())
+	return redacted
+}
+
+// redactStruct masks the secret fields of value, descending into nested
+// structs and lists of structs such as auth.api_keys.
+func redactStruct(value reflect.Value) {
+	for i := 0; i < value.NumField(); i++ {
+		fieldValue := value.Field(i)
+		mode := value.Type().Field(i).Tag.Get(secretTag)
+		if fieldValue.IsZero() || (mode == "" && !holdsStructs(fieldValue.Type())) {
+			continue
+		}
+		redactValue(fieldValue, mode)
+	}
+}
+
+func holdsStructs(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct || (t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Struct)
+}
+
+// redactValue masks value in place. Slices are copied first because Redact
+// works on a shallow copy whose slices are shared with the caller's config.
+func redactValue(value reflect.Value, mode string) {
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(redactString(value.String(), mode))
+	case reflect.Slice:
+		redacted := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		reflect.Copy(redacted, value)
+		for i := 0; i < redacted.Len(); i++ {
+			redactValue(redacted.Index(i), mode)
+		}
+		value.Set(redacted)
+	case reflect.Struct:
+		redactStruct(value)
+	}
+}
+
+func redactString(raw, mode string) string {
+	if mode != secretUserinfo {
+		return redactedValue
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return redactedValue
+	}
+	if parsed.User == nil {
+		return raw
+	}
+	parsed.User = url.User(redactedValue)
+	return parsed.String()
+}
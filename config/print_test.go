@@ -0,0 +1,98 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSecret      = "s3cr3t"
+	testAPIKeyHash  = "sha256:2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b"
+	testUpstreamURL = "https://bookshop:" + testSecret + "@books.example.com/api/books"
+)
+
+type secretsFixture struct {
+	Token  string          `json:"token" secret:"true"`
+	Keys   []string        `json:"keys" secret:"true"`
+	Public string          `json:"public"`
+	Empty  string          `json:"empty" secret:"true"`
+	Nested []nestedFixture `json:"nested"`
+}
+
+type nestedFixture struct {
+	Name  string `json:"name"`
+	Token string `json:"token" secret:"true"`
+}
+
+func TestRedactStruct_MasksSecretFields(t *testing.T) {
+	fixture := secretsFixture{
+		Token:  testSecret,
+		Keys:   []string{testSecret, testSecret},
+		Public: testSecret,
+		Nested: []nestedFixture{{Name: testSecret, Token: testSecret}},
+	}
+	originalKeys, originalNested := fixture.Keys, fixture.Nested
+
+	redactStruct(reflect.ValueOf(&fixture).Elem())
+
+	require.Equal(t, redactedValue, fixture.Token)
+	require.Equal(t, []string{redactedValue, redactedValue}, fixture.Keys)
+	require.Equal(t, testSecret, fixture.Public)
+	require.Empty(t, fixture.Empty)
+	require.Equal(t, []nestedFixture{{Name: testSecret, Token: redactedValue}}, fixture.Nested)
+	require.Equal(t, []string{testSecret, testSecret}, originalKeys)
+	require.Equal(t, testSecret, originalNested[0].Token)
+}
+
+func TestRedact_MasksConfigSecrets(t *testing.T) {
+	cfg := Default()
+	cfg.Upstream.URL = testUpstreamURL
+	cfg.Upstream.Providers = []UpstreamProvider{{Name: "mirror", URL: testUpstreamURL}}
+	cfg.Upstream.Fallbacks = []UpstreamFallback{{Name: "backup", URL: testUpstreamURL}, {Name: "file", File: "catalog.json"}}
+	cfg.Auth.APIKeys = []APIKeyConfig{{Name: "reader", Hash: testAPIKeyHash, Scopes: []string{"books:read"}}}
+
+	redacted := Redact(cfg)
+
+	redactedURL := "https://REDACTED@books.example.com/api/books"
+	require.Equal(t, redactedURL, redacted.Upstream.URL)
+	require.Equal(t, redactedURL, redacted.Upstream.Providers[0].URL)
+	require.Equal(t, redactedURL, redacted.Upstream.Fallbacks[0].URL)
+	require.Equal(t, "catalog.json", redacted.Upstream.Fallbacks[1].File)
+	require.Equal(t, []APIKeyConfig{{Name: "reader", Hash: redactedValue, Scopes: []string{"books:read"}}}, redacted.Auth.APIKeys)
+	require.Equal(t, testAPIKeyHash, cfg.Auth.APIKeys[0].Hash)
+	require.Equal(t, testUpstreamURL, cfg.Upstream.Providers[0].URL)
+}
+
+func TestRedact_KeepsURLsWithoutCredentials(t *testing.T) {
+	cfg := Default()
+
+	require.Equal(t, cfg.Upstream.URL, Redact(cfg).Upstream.URL)
+}
+
+func TestPrint_WritesEffectiveConfig(t *testing.T) {
+	var out bytes.Buffer
+	cfg := Default()
+
+	err := Print(&out, cfg)
+
+	require.NoError(t, err)
+	var printed Config
+	require.NoError(t, json.Unmarshal(out.Bytes(), &printed))
+	require.Equal(t, cfg, printed)
+}
+
+func TestPrint_RedactsAPIKeyHashes(t *testing.T) {
+	var out bytes.Buffer
+	cfg := Default()
+	cfg.Auth.APIKeys = []APIKeyConfig{{Name: "reader", Hash: testAPIKeyHash}}
+
+	err := Print(&out, cfg)
+
+	require.NoError(t, err)
+	require.NotContains(t, out.String(), testAPIKeyHash)
+	require.Contains(t, out.String(), redactedValue)
+}
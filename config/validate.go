@@ -0,0 +1,182 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// PrimaryProviderName is what the upstream is called in a failover chain, so
+// fallbacks may not use it.
+const PrimaryProviderName = "primary"
+
+var (
+	traceExporters = []string{"none", "stdout", "otlp"}
+	authMethods    = []string{"api_key", "jwt"}
+	encodings      = []string{"br", "zstd", "gzip"}
+	policies       = []string{"reject", "skip", "quarantine"}
+	decodingModes  = []string{"strict", "lenient"}
+	roundings      = []string{"nearest", "down", "up", "reject"}
+	mergeKeys      = []string{"id", "name_author"}
+	conflicts      = []string{"priority", "freshest"}
+)
+
+func (c Config) Validate() error {
+	var errs []error
+	require := func(ok bool, key, reason string) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%w: %s %s", ErrInvalidConfig, key, reason))
+		}
+	}
+
+	require(c.Server.Address != "", "server.address", "must not be empty")
+	require(c.Server.ReadTimeout >= 0, "server.read_timeout", "must not be negative")
+	require(c.Server.ReadHeaderTimeout >= 0, "server.read_header_timeout", "must not be negative")
+	require(c.Server.WriteTimeout >= 0, "server.write_timeout", "must not be negative")
+	require(c.Server.IdleTimeout >= 0, "server.idle_timeout", "must not be negative")
+	require(c.Server.MaxHeaderBytes > 0, "server.max_header_bytes", "must be positive")
+	require(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout", "must be positive")
+	require(c.GRPC.Address != "", "grpc.address", "must not be empty")
+	require(c.GRPC.Address != c.Server.Address, "grpc.address", "must differ from server.address")
+	require(len(c.Upstream.Providers) > 0 || isHTTPURL(c.Upstream.URL), "upstream.url", "must be an absolute http(s) URL")
+	require(c.Upstream.Timeout > 0, "upstream.timeout", "must be positive")
+	require(c.Upstream.MaxBodyBytes > 0, "upstream.max_body_bytes", "must be positive")
+	require(slices.Contains(policies, c.Upstream.ValidationPolicy), "upstream.validation_policy", "must be one of reject, skip, quarantine")
+	require(slices.Contains(decodingModes, c.Upstream.DecodingMode), "upstream.decoding_mode", "must be one of strict, lenient")
+	require(slices.Contains(roundings, c.Upstream.Rounding), "upstream.rounding", "must be one of nearest, down, up, reject")
+	if len(c.Upstream.Providers) > 0 {
+		require(slices.Contains(mergeKeys, c.Upstream.MergeKey), "upstream.merge_key", "must be one of id, name_author")
+		require(slices.Contains(conflicts, c.Upstream.ConflictPolicy), "upstream.conflict_policy", "must be one of priority, freshest")
+		names := make(map[string]bool)
+		for i, provider := range c.Upstream.Providers {
+			key := fmt.Sprintf("upstream.providers[%d]", i)
+			require(provider.Name != "", key+".name", "must not be empty")
+			require(!names[provider.Name], key+".name", "must be unique")
+			require(isHTTPURL(provider.URL), key+".url", "must be an absolute http(s) URL")
+			names[provider.Name] = true
+		}
+	}
+	if len(c.Upstream.Fallbacks) > 0 {
+		require(c.Upstream.FailoverCooldown > 0, "upstream.failover_cooldown", "must be positive")
+		names := map[string]bool{PrimaryProviderName: true}
+		for i, fallback := range c.Upstream.Fallbacks {
+			key := fmt.Sprintf("upstream.fallbacks[%d]", i)
+			require(fallback.Name != "", key+".name", "must not be empty")
+			require(!names[fallback.Name], key+".name", "must be unique and not "+PrimaryProviderName)
+			require((fallback.URL == "") != (fallback.File == ""), key, "must set exactly one of url or file")
+			require(fallback.URL == "" || isHTTPURL(fallback.URL), key+".url", "must be an absolute http(s) URL")
+			names[fallback.Name] = true
+		}
+	}
+	require(c.Catalog.PollInterval > 0, "catalog.poll_interval", "must be positive")
+	require(c.GraphQL.MaxDepth > 0, "graphql.max_depth", "must be positive")
+	require(c.GraphQL.MaxComplexity > 0, "graphql.max_complexity", "must be positive")
+	require(c.GraphQL.MaxQueryBytes > 0, "graphql.max_query_bytes", "must be positive")
+	require(c.Subscriptions.MaxPerSession > 0, "subscriptions.max_per_session", "must be positive")
+	require(c.Health.CheckTimeout > 0, "health.check_timeout", "must be positive")
+	require(c.Health.MaxFetchAge > 0, "health.max_fetch_age", "must be positive")
+	require(slices.Contains(traceExporters, c.Tracing.Exporter), "tracing.exporter", "must be one of none, stdout, otlp")
+	require(c.Tracing.Exporter != "otlp" || c.Tracing.OTLPEndpoint != "", "tracing.otlp_endpoint", "must not be empty when exporting over OTLP")
+	require(c.Tracing.ServiceName != "", "tracing.service_name", "must not be empty")
+	require(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio", "must be between 0 and 1")
+	require(isLogLevel(c.Logging.Level), "logging.level", "must be one of debug, info, warn, error")
+	require(c.Logging.SampleEvery > 0, "logging.sample_every", "must be positive")
+	if c.Auth.Enabled {
+		require(len(c.Auth.Methods) > 0, "auth.methods", "must not be empty when auth is enabled")
+		for _, method := range c.Auth.Methods {
+			require(slices.Contains(authMethods, method), "auth.methods", "must only contain api_key or jwt")
+		}
+		if slices.Contains(c.Auth.Methods, "api_key") {
+			require(len(c.Auth.APIKeys) > 0 || c.Auth.APIKeysFile != "", "auth.api_keys", "must not be empty when using api_key auth without auth.api_keys_file")
+		}
+		if slices.Contains(c.Auth.Methods, "jwt") {
+			require(c.Auth.JWT.JWKSFile != "", "auth.jwt.jwks_file", "must not be empty when using jwt auth")
+			require(c.Auth.JWT.Issuer != "", "auth.jwt.issuer", "must not be empty when using jwt auth")
+			require(c.Auth.JWT.Audience != "", "auth.jwt.audience", "must not be empty when using jwt auth")
+		}
+	}
+	require(c.Auth.JWT.Leeway >= 0, "auth.jwt.leeway", "must not be negative")
+	for _, proxy := range c.Server.TrustedProxies {
+		require(isIPOrCIDR(proxy), "server.trusted_proxies", fmt.Sprintf("contains invalid IP or CIDR %q", proxy))
+	}
+	if c.RateLimit.Enabled {
+		require(c.RateLimit.RequestsPerSecond > 0, "rate_limit.requests_per_second", "must be positive")
+		require(c.RateLimit.Burst > 0, "rate_limit.burst", "must be positive")
+		require(c.RateLimit.CleanupInterval > 0, "rate_limit.cleanup_interval", "must be positive")
+		if c.Auth.Enabled {
+			require(c.RateLimit.IPRequestsPerSecond > 0, "rate_limit.ip_requests_per_second", "must be positive when auth is enabled")
+			require(c.RateLimit.IPBurst > 0, "rate_limit.ip_burst", "must be positive when auth is enabled")
+		}
+		for i, route := range c.RateLimit.Routes {
+			key := fmt.Sprintf("rate_limit.routes[%d]", i)
+			require(strings.HasPrefix(route.Route, "/"), key+".route", "must start with /")
+			require(route.RequestsPerSecond > 0, key+".requests_per_second", "must be positive")
+			require(route.Burst > 0, key+".burst", "must be positive")
+		}
+	}
+	for i, key := range c.Auth.APIKeys {
+		require(key.Name != "", fmt.Sprintf("auth.api_keys[%d].name", i), "must not be empty")
+		require(key.Hash != "", fmt.Sprintf("auth.api_keys[%d].hash", i), "must not be empty")
+	}
+
+	for _, origin := range c.CORS.AllowedOrigins {
+		require(isOriginPattern(origin), "cors.allowed_origins", fmt.Sprintf("contains invalid origin %q", origin))
+	}
+	require(!c.CORS.AllowCredentials || !slices.Contains(c.CORS.AllowedOrigins, "*"), "cors.allowed_origins", `must not contain "*" when cors.allow_credentials is set`)
+	require(len(c.CORS.AllowedOrigins) == 0 || len(c.CORS.AllowedMethods) > 0, "cors.allowed_methods", "must not be empty when origins are allowed")
+	require(c.CORS.MaxAge >= 0, "cors.max_age", "must not be negative")
+	require(c.HTTPCache.MaxAge >= 0, "http_cache.max_age", "must not be negative")
+	require(!c.Snapshot.Enabled || c.Snapshot.Path != "", "snapshot.path", "must not be empty when snapshots are enabled")
+	if c.Refresh.Enabled {
+		require(c.Refresh.Interval > 0, "refresh.interval", "must be positive")
+		require(c.Refresh.Jitter >= 0 && c.Refresh.Jitter < c.Refresh.Interval, "refresh.jitter", "must not be negative and must be less than refresh.interval")
+	}
+	if c.Compression.Enabled {
+		require(c.Compression.MinSize >= 0, "compression.min_size", "must not be negative")
+		require(len(c.Compression.Encodings) > 0, "compression.encodings", "must not be empty when compression is enabled")
+		for _, encoding := range c.Compression.Encodings {
+			require(slices.Contains(encodings, encoding), "compression.encodings", "must only contain br, zstd or gzip")
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func isLogLevel(raw string) bool {
+	var level slog.Level
+	return level.UnmarshalText([]byte(raw)) == nil
+}
+
+func isIPOrCIDR(raw string) bool {
+	if _, err := netip.ParsePrefix(raw); err == nil {
+		return true
+	}
+	_, err := netip.ParseAddr(raw)
+	return err == nil
+}
+
+// isOriginPattern accepts "*", or a bare http(s) origin whose host may start
+// with a "*." wildcard label.
+func isOriginPattern(raw string) bool {
+	if raw == "*" {
+		return true
+	}
+	parsed, err := url.Parse(strings.Replace(raw, "://*.", "://wildcard.", 1))
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != "" &&
+		parsed.Path == "" && parsed.RawQuery == "" && parsed.User == nil && !strings.Contains(parsed.Host, "*")
+}
+
+func isHTTPURL(raw string) bool {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
@@ -0,0 +1,256 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const invalidUpstreamURL = "ftp://example.com/books"
+
+func TestValidate_Defaults(t *testing.T) {
+	err := Default().Validate()
+
+	require.NoError(t, err)
+}
+
+func TestValidate_ReportsEveryProblem(t *testing.T) {
+	cfg := Default()
+	cfg.Server.Address = ""
+	cfg.Upstream.URL = invalidUpstreamURL
+	cfg.Catalog.PollInterval = 0
+
+	err := cfg.Validate()
+
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	require.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 3)
+}
+
+func TestValidate_ConflictingAddresses(t *testing.T) {
+	cfg := Default()
+	cfg.GRPC.Address = cfg.Server.Address
+
+	err := cfg.Validate()
+
+	require.ErrorIs(t, err, ErrInvalidConfig)
+}
+
+func TestValidate_Tracing(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*Config)
+	}{
+		{"unknown exporter", func(c *Config) { c.Tracing.Exporter = "jaeger" }},
+		{"otlp without endpoint", func(c *Config) {
+			c.Tracing.Exporter = "otlp"
+			c.Tracing.OTLPEndpoint = ""
+		}},
+		{"sample ratio above one", func(c *Config) { c.Tracing.SampleRatio = 1.5 }},
+		{"empty service name", func(c *Config) { c.Tracing.ServiceName = "" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.mutate(&cfg)
+
+			err := cfg.Validate()
+
+			require.ErrorIs(t, err, ErrInvalidConfig)
+		})
+	}
+}
+
+func TestValidate_Logging(t *testing.T) {
+	cfg := Default()
+	cfg.Logging.Level = "verbose"
+	cfg.Logging.SampleEvery = 0
+
+	err := cfg.Validate()
+
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	require.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 2)
+}
+
+func TestValidate_AuthRequiresKeys(t *testing.T) {
+	cfg := Default()
+	cfg.Auth.Enabled = true
+
+	err := cfg.Validate()
+
+	require.ErrorIs(t, err, ErrInvalidConfig)
+}
+
+func TestValidate_AuthKeysFromFile(t *testing.T) {
+	cfg := Default()
+	cfg.Auth.Enabled = true
+	cfg.Auth.APIKeysFile = "keys.json"
+
+	err := cfg.Validate()
+
+	require.NoError(t, err)
+}
+
+func TestValidate_JWTRequiresSettings(t *testing.T) {
+	cfg := Default()
+	cfg.Auth.Enabled = true
+	cfg.Auth.Methods = []string{"jwt", "saml"}
+
+	err := cfg.Validate()
+
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	require.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 4)
+}
+
+func TestValidate_RateLimit(t *testing.T) {
+	cfg := Default()
+	cfg.Server.TrustedProxies = []string{"10.0.0.0/8", "192.168.1.1", "proxy.internal"}
+	cfg.RateLimit.Enabled = true
+	cfg.RateLimit.Burst = 0
+	cfg.RateLimit.Routes = []RouteRateLimit{{Route: "v2/books", RequestsPerSecond: 1, Burst: 1}}
+
+	err := cfg.Validate()
+
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	require.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 3)
+}
+
+func TestValidate_RateLimitBeforeAuth(t *testing.T) {
+	cfg := Default()
+	cfg.RateLimit.Enabled = true
+	cfg.RateLimit.IPRequestsPerSecond = 0
+	cfg.RateLimit.IPBurst = 0
+	withoutAuth := cfg
+	cfg.Auth.Enabled = true
+	cfg.Auth.APIKeysFile = "keys.json"
+
+	err := cfg.Validate()
+
+	require.NoError(t, withoutAuth.Validate())
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	require.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 2)
+}
+
+func TestValidate_CORS(t *testing.T) {
+	valid := Default()
+	valid.CORS.AllowedOrigins = []string{"https://dashboard.example.com", "https://*.example.com", "http://localhost:5173"}
+	invalid := Default()
+	invalid.CORS.AllowedOrigins = []string{"*", "dashboard.example.com", "https://example.com/path", "https://foo.*.com"}
+	invalid.CORS.AllowCredentials = true
+
+	validErr := valid.Validate()
+	invalidErr := invalid.Validate()
+
+	require.NoError(t, validErr)
+	require.ErrorIs(t, invalidErr, ErrInvalidConfig)
+	require.Len(t, invalidErr.(interface{ Unwrap() []error }).Unwrap(), 4)
+}
+
+func TestValidate_Compression(t *testing.T) {
+	cfg := Default()
+	cfg.Compression.MinSize = -1
+	cfg.Compression.Encodings = []string{"gzip", "deflate"}
+
+	err := cfg.Validate()
+
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	require.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 2)
+}
+
+func TestValidate_UpstreamDecoding(t *testing.T) {
+	cfg := Default()
+	cfg.Upstream.MaxBodyBytes = 0
+	cfg.Upstream.ValidationPolicy = "ignore"
+	cfg.Upstream.DecodingMode = "loose"
+	cfg.Upstream.Rounding = "banker"
+
+	err := cfg.Validate()
+
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	require.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 4)
+}
+
+func TestValidate_GraphQL(t *testing.T) {
+	cfg := Default()
+	cfg.GraphQL.MaxDepth = 0
+	cfg.GraphQL.MaxQueryBytes = 0
+
+	err := cfg.Validate()
+
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	require.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 2)
+}
+
+func TestValidate_UpstreamProviders(t *testing.T) {
+	cfg := Default()
+	cfg.Upstream.URL = ""
+	cfg.Upstream.MergeKey = "isbn"
+	cfg.Upstream.Providers = []UpstreamProvider{
+		{Name: "primary", URL: "https://primary.example.com/books", Priority: 1},
+		{Name: "primary", URL: "ftp://backup.example.com/books", Priority: 2},
+	}
+
+	err := cfg.Validate()
+
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	require.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 3)
+}
+
+func TestValidate_UpstreamFallbacks(t *testing.T) {
+	cfg := Default()
+	cfg.Upstream.FailoverCooldown = 0
+	cfg.Upstream.Fallbacks = []UpstreamFallback{
+		{Name: "primary", URL: "https://backup.example.com/books"},
+		{Name: "snapshot", URL: "https://backup.example.com/books", File: "/var/lib/bookshop/catalog.json"},
+		{Name: "mirror", URL: "backup.example.com"},
+	}
+
+	err := cfg.Validate()
+
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	require.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 4)
+}
+
+func TestValidate_Snapshot(t *testing.T) {
+	cfg := Default()
+	cfg.Snapshot.Enabled = true
+	cfg.Snapshot.Path = ""
+
+	err := cfg.Validate()
+
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	require.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 1)
+}
+
+func TestValidate_Refresh(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		interval time.Duration
+		jitter   time.Duration
+		errs     int
+	}{
+		{name: "valid", enabled: true, interval: time.Minute, jitter: 10 * time.Second},
+		{name: "no jitter", enabled: true, interval: time.Minute},
+		{name: "zero interval", enabled: true, errs: 2},
+		{name: "negative jitter", enabled: true, interval: time.Minute, jitter: -time.Second, errs: 1},
+		{name: "jitter as long as interval", enabled: true, interval: time.Minute, jitter: time.Minute, errs: 1},
+		{name: "disabled ignores schedule"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Refresh = RefreshConfig{Enabled: tt.enabled, Interval: Duration(tt.interval), Jitter: Duration(tt.jitter)}
+
+			err := cfg.Validate()
+
+			if tt.errs == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrInvalidConfig)
+			require.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), tt.errs)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"educabot.com/bookshop/service"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	adminHandler struct {
+		refresher service.CatalogRefresher
+	}
+
+	AdminHandler interface {
+		RefreshCatalog(ctx *gin.Context)
+	}
+
+	catalogRefreshResponse struct {
+		Books       int       `json:"books"`
+		Source      string    `json:"source,omitempty"`
+		RefreshedAt time.Time `json:"refreshed_at"`
+	}
+)
+
+func NewAdminHandler(refresher service.CatalogRefresher) AdminHandler {
+	return &adminHandler{refresher: refresher}
+}
+
+// RefreshCatalog refreshes the catalog now instead of waiting for the next
+// scheduled refresh. On failure the previous catalog keeps being served.
+func (h *adminHandler) RefreshCatalog(ctx *gin.Context) {
+	snapshot, err := h.refresher.Refresh(ctx.Request.Context())
+	if err != nil {
+		respondWithErrorEnvelope(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, catalogRefreshResponse{
+		Books:       len(snapshot.Books),
+		Source:      snapshot.Source,
+		RefreshedAt: snapshot.RefreshedAt,
+	})
+}
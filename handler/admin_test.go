@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/models"
+	"educabot.com/bookshop/service"
+	"educabot.com/bookshop/test/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	pathRefreshCatalog = "/admin/catalog/refresh"
+	testRefreshSource  = "primary"
+)
+
+func setupAdminRouter(h AdminHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST(pathRefreshCatalog, h.RefreshCatalog)
+	return r
+}
+
+func TestRefreshCatalog_Success(t *testing.T) {
+	refreshedAt := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	refresher := mocks.NewMockCatalogRefresher().WithSnapshot(&models.CatalogSnapshot{
+		Books:       []models.Book{{ID: 1}, {ID: 2}},
+		Source:      testRefreshSource,
+		RefreshedAt: refreshedAt,
+	})
+	router := setupAdminRouter(NewAdminHandler(refresher))
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, pathRefreshCatalog, nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response catalogRefreshResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Equal(t, catalogRefreshResponse{Books: 2, Source: testRefreshSource, RefreshedAt: refreshedAt}, response)
+	require.Equal(t, 1, refresher.Refreshes)
+}
+
+func TestRefreshCatalog_UpstreamFailure(t *testing.T) {
+	err := fmt.Errorf("%w: %w", service.ErrFetchingBooks, errors.New("connection refused"))
+	router := setupAdminRouter(NewAdminHandler(mocks.NewMockCatalogRefresher().WithError(err)))
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, pathRefreshCatalog, nil))
+
+	require.Equal(t, http.StatusBadGateway, rec.Code)
+	envelope := decodeErrorEnvelope(t, rec)
+	require.Equal(t, errorCodeUpstreamUnavailable, envelope.Error.Code)
+	require.NotContains(t, envelope.Error.Message, "connection refused")
+}
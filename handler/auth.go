@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"educabot.com/bookshop/auth"
+	"github.com/gin-gonic/gin"
+)
+
+const headerWWWAuthenticate = "WWW-Authenticate"
+
+// Authenticate rejects requests the authenticator cannot identify with a 401
+// error envelope, challenging for the credentials it accepts, and stores the principal on the request context otherwise.
+// Requests for any of exemptPaths, or anything below them, skip
+// authentication so probes and docs stay reachable.
+func Authenticate(authenticator auth.Authenticator, exemptPaths []string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if isExemptPath(ctx.Request.URL.Path, exemptPaths) {
+			ctx.Next()
+			return
+		}
+		principal, err := authenticator.Authenticate(ctx.Request)
+		if err != nil {
+			if challenger, ok := authenticator.(auth.Challenger); ok {
+				ctx.Header(headerWWWAuthenticate, challenger.Challenge())
+			}
+			abortWithErrorEnvelope(ctx, http.StatusUnauthorized, err.Error())
+			return
+		}
+		ctx.Request = ctx.Request.WithContext(auth.WithPrincipal(ctx.Request.Context(), principal))
+		ctx.Next()
+	}
+}
+
+func isExemptPath(path string, exemptPaths []string) bool {
+	for _, exempt := range exemptPaths {
+		if path == exempt || strings.HasPrefix(path, strings.TrimSuffix(exempt, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireScope only lets through requests whose authenticated principal holds
+// scope. It must run after Authenticate.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		principal, ok := auth.PrincipalFromContext(ctx.Request.Context())
+		if !ok {
+			abortWithErrorEnvelope(ctx, http.StatusUnauthorized, auth.ErrMissingCredentials.Error())
+			return
+		}
+		if !principal.HasScope(scope) {
+			abortWithErrorEnvelope(ctx, http.StatusForbidden, fmt.Sprintf("missing scope %s", scope))
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"educabot.com/bookshop/auth"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAPIKey     = "s3cr3t-key"
+	testKeyName    = "reporting"
+	pathProtected  = "/v2/books"
+	pathDocsPage   = "/docs/index.html"
+	testScopeRead  = "books:read"
+	testUnknownKey = "unknown"
+)
+
+func setupAuthRouter(t *testing.T, exempt []string) (*gin.Engine, *auth.Principal) {
+	gin.SetMode(gin.TestMode)
+	authenticator, err := auth.NewAPIKeyAuthenticator([]auth.APIKey{
+		{Name: testKeyName, Hash: auth.HashAPIKey(testAPIKey), Scopes: []string{testScopeRead}},
+	})
+	require.NoError(t, err)
+	var seen auth.Principal
+	record := func(ctx *gin.Context) {
+		seen, _ = auth.PrincipalFromContext(ctx.Request.Context())
+		ctx.Status(http.StatusOK)
+	}
+	r := gin.New()
+	r.Use(Authenticate(authenticator, exempt))
+	r.GET(pathProtected, record)
+	r.GET(pathLiveness, record)
+	r.GET(pathDocsPage, record)
+	return r, &seen
+}
+
+func TestAuthenticate_ValidKey(t *testing.T) {
+	router, seen := setupAuthRouter(t, nil)
+	req := httptest.NewRequest(http.MethodGet, pathProtected, nil)
+	req.Header.Set(auth.HeaderAPIKey, testAPIKey)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, testKeyName, seen.Name)
+	require.True(t, seen.HasScope(testScopeRead))
+}
+
+func TestAuthenticate_Rejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		message string
+	}{
+		{"missing key", "", auth.ErrMissingCredentials.Error()},
+		{"unknown key", testUnknownKey, auth.ErrInvalidCredentials.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, _ := setupAuthRouter(t, nil)
+			req := httptest.NewRequest(http.MethodGet, pathProtected, nil)
+			if tt.key != "" {
+				req.Header.Set(auth.HeaderAPIKey, tt.key)
+			}
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, http.StatusUnauthorized, rec.Code)
+			envelope := decodeErrorEnvelope(t, rec)
+			require.Equal(t, errorCodeUnauthenticated, envelope.Error.Code)
+			require.Equal(t, tt.message, envelope.Error.Message)
+			require.Equal(t, `APIKey header="X-API-Key"`, rec.Header().Get(headerWWWAuthenticate))
+		})
+	}
+}
+
+func TestAuthenticate_ExemptPaths(t *testing.T) {
+	router, _ := setupAuthRouter(t, []string{pathLiveness, "/docs/"})
+
+	for _, path := range []string{pathLiveness, pathDocsPage} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, rec.Code, path)
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, pathProtected, nil))
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func setupScopedRouter(principal *auth.Principal) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	if principal != nil {
+		r.Use(func(ctx *gin.Context) {
+			ctx.Request = ctx.Request.WithContext(auth.WithPrincipal(ctx.Request.Context(), *principal))
+		})
+	}
+	r.GET(pathProtected, RequireScope(testScopeRead), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	return r
+}
+
+func TestRequireScope(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		status    int
+		code      string
+	}{
+		{"has scope", &auth.Principal{Scopes: []string{testScopeRead}}, http.StatusOK, ""},
+		{"admin", &auth.Principal{Scopes: []string{auth.ScopeAdmin}}, http.StatusOK, ""},
+		{"missing scope", &auth.Principal{Scopes: []string{auth.ScopeBooksWrite}}, http.StatusForbidden, errorCodePermissionDenied},
+		{"unauthenticated", nil, http.StatusUnauthorized, errorCodeUnauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupScopedRouter(tt.principal)
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, pathProtected, nil))
+
+			require.Equal(t, tt.status, rec.Code)
+			if tt.code != "" {
+				require.Equal(t, tt.code, decodeErrorEnvelope(t, rec).Error.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"educabot.com/bookshop/models"
+	"educabot.com/bookshop/service"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	queryPage     = "page"
+	queryPageSize = "page_size"
+
+	defaultPage = 1
+)
+
+type (
+	booksHandler struct {
+		booksService service.BooksService
+	}
+
+	BooksHandler interface {
+		ListBooks(ctx *gin.Context)
+	}
+
+	booksPageResponse struct {
+		Data       []models.Book      `json:"data"`
+		Pagination paginationResponse `json:"pagination"`
+	}
+
+	paginationResponse struct {
+		Page       int `json:"page"`
+		PageSize   int `json:"page_size"`
+		Total      int `json:"total"`
+		TotalPages int `json:"total_pages"`
+	}
+)
+
+func NewBooksHandler(booksService service.BooksService) BooksHandler {
+	return &booksHandler{booksService: booksService}
+}
+
+func (h *booksHandler) ListBooks(ctx *gin.Context) {
+	pagination, err := parsePagination(ctx)
+	if err != nil {
+		respondWithErrorEnvelope(ctx, err)
+		return
+	}
+
+	page, err := h.booksService.ListBooks(ctx.Request.Context(), models.BooksQuery{Pagination: pagination})
+	if err != nil {
+		respondWithErrorEnvelope(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, newBooksPageResponse(page))
+}
+
+func parsePagination(ctx *gin.Context) (models.Pagination, error) {
+	page, err := strconv.Atoi(ctx.DefaultQuery(queryPage, strconv.Itoa(defaultPage)))
+	if err != nil {
+		return models.Pagination{}, fmt.Errorf("%w: %s must be an integer", service.ErrInvalidPagination, queryPage)
+	}
+	pageSize, err := strconv.Atoi(ctx.DefaultQuery(queryPageSize, strconv.Itoa(service.DefaultPageSize)))
+	if err != nil {
+		return models.Pagination{}, fmt.Errorf("%w: %s must be an integer", service.ErrInvalidPagination, queryPageSize)
+	}
+	return models.Pagination{Page: page, PageSize: pageSize}, nil
+}
+
+func newBooksPageResponse(page models.BooksPage) booksPageResponse {
+	books := page.Books
+	if books == nil {
+		books = []models.Book{}
+	}
+	return booksPageResponse{
+		Data: books,
+		Pagination: paginationResponse{
+			Page:       page.Page,
+			PageSize:   page.PageSize,
+			Total:      page.Total,
+			TotalPages: page.TotalPages,
+		},
+	}
+}
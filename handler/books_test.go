@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"educabot.com/bookshop/models"
+	"educabot.com/bookshop/service"
+	"educabot.com/bookshop/test/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	pathBooks            = "/books"
+	pathBooksInvalidPage = "/books?page=abc"
+	pathBooksInvalidSize = "/books?page_size=abc"
+	pathBooksSecondPage  = "/books?page=2&page_size=1"
+	testListTotal        = 2
+	testListTotalPages   = 2
+	testListPage         = 2
+	testListPageSize     = 1
+)
+
+func setupBooksRouter(h BooksHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/books", h.ListBooks)
+	return r
+}
+
+func TestListBooks_Success(t *testing.T) {
+	mockSvc := mocks.NewMockBooksService().WithBooksPage(models.BooksPage{
+		Books:      []models.Book{{Name: testBookLion}},
+		Page:       testListPage,
+		PageSize:   testListPageSize,
+		Total:      testListTotal,
+		TotalPages: testListTotalPages,
+	})
+	router := setupBooksRouter(NewBooksHandler(mockSvc))
+	req := httptest.NewRequest(http.MethodGet, pathBooksSecondPage, nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response booksPageResponse
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	require.NoError(t, err)
+	require.Len(t, response.Data, testListPageSize)
+	require.Equal(t, testBookLion, response.Data[0].Name)
+	require.Equal(t, testListPage, response.Pagination.Page)
+	require.Equal(t, testListTotal, response.Pagination.Total)
+	require.Equal(t, testListTotalPages, response.Pagination.TotalPages)
+}
+
+func TestListBooks_EmptyPage(t *testing.T) {
+	mockSvc := mocks.NewMockBooksService()
+	router := setupBooksRouter(NewBooksHandler(mockSvc))
+	req := httptest.NewRequest(http.MethodGet, pathBooks, nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response booksPageResponse
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	require.NoError(t, err)
+	require.NotNil(t, response.Data)
+	require.Empty(t, response.Data)
+}
+
+func TestListBooks_InvalidPage(t *testing.T) {
+	mockSvc := mocks.NewMockBooksService()
+	router := setupBooksRouter(NewBooksHandler(mockSvc))
+	req := httptest.NewRequest(http.MethodGet, pathBooksInvalidPage, nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Equal(t, errorCodeInvalidArgument, decodeErrorEnvelope(t, rec).Error.Code)
+}
+
+func TestListBooks_InvalidPageSize(t *testing.T) {
+	mockSvc := mocks.NewMockBooksService()
+	router := setupBooksRouter(NewBooksHandler(mockSvc))
+	req := httptest.NewRequest(http.MethodGet, pathBooksInvalidSize, nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Equal(t, errorCodeInvalidArgument, decodeErrorEnvelope(t, rec).Error.Code)
+}
+
+func TestListBooks_ServiceInvalidPagination(t *testing.T) {
+	mockSvc := mocks.NewMockBooksService().WithError(service.ErrInvalidPagination)
+	router := setupBooksRouter(NewBooksHandler(mockSvc))
+	req := httptest.NewRequest(http.MethodGet, pathBooks, nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestListBooks_FetchingError(t *testing.T) {
+	mockSvc := mocks.NewMockBooksService().WithError(service.ErrFetchingBooks)
+	router := setupBooksRouter(NewBooksHandler(mockSvc))
+	req := httptest.NewRequest(http.MethodGet, pathBooks, nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusBadGateway, rec.Code)
+	require.Equal(t, errorCodeUpstreamUnavailable, decodeErrorEnvelope(t, rec).Error.Code)
+}
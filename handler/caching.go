@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"educabot.com/bookshop/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	headerETag            = "ETag"
+	headerLastModified    = "Last-Modified"
+	headerCacheControl    = "Cache-Control"
+	headerIfNoneMatch     = "If-None-Match"
+	headerIfModifiedSince = "If-Modified-Since"
+
+	etagLength     = 32
+	weakETagPrefix = "W/"
+)
+
+type (
+	CatalogVersioner interface {
+		Version() (models.CatalogVersion, bool)
+	}
+
+	HTTPCachePolicy struct {
+		MaxAge time.Duration
+		// Private keeps shared caches such as CDNs from storing responses,
+		// which is required once responses depend on who is asking.
+		Private bool
+	}
+
+	cachingWriter struct {
+		gin.ResponseWriter
+	}
+)
+
+// Cacheable adds validators derived from the catalog version and the request
+// query, and answers matching conditional GETs with 304 without running the
+// handler, so no upstream call is made. The version comes from the catalog
+// watcher while the body comes from the catalog repository, so the two can
+// disagree until the watcher next polls; the ETag is weak for that reason,
+// and a 304 can be stale by at most one poll interval.
+func Cacheable(versioner CatalogVersioner, policy HTTPCachePolicy) gin.HandlerFunc {
+	cacheControl := cacheControlValue(policy)
+	return func(ctx *gin.Context) {
+		method := ctx.Request.Method
+		version, ok := versioner.Version()
+		if !ok || (method != http.MethodGet && method != http.MethodHead) {
+			ctx.Next()
+			return
+		}
+
+		etag := catalogETag(version, ctx.Request)
+		lastModified := version.ModifiedAt.UTC().Format(http.TimeFormat)
+		ctx.Header(headerETag, etag)
+		ctx.Header(headerLastModified, lastModified)
+		ctx.Header(headerCacheControl, cacheControl)
+
+		if notModified(ctx.Request, etag, version.ModifiedAt) {
+			ctx.AbortWithStatus(http.StatusNotModified)
+			return
+		}
+
+		// Only successful responses are cacheable; drop the validators if
+		// the handler ends up answering with an error.
+		ctx.Writer = cachingWriter{ResponseWriter: ctx.Writer}
+		ctx.Next()
+	}
+}
+
+func (w cachingWriter) WriteHeader(code int) {
+	if code != http.StatusOK {
+		header := w.Header()
+		header.Del(headerETag)
+		header.Del(headerLastModified)
+		header.Del(headerCacheControl)
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap lets http.ResponseController reach the connection.
+func (w cachingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// catalogETag is weak: equal versions and equal queries produce equivalent
+// responses, not necessarily the same bytes, since the served catalog can be
+// a poll ahead of the version. Query parameters are re-encoded in sorted order
+// so that reordering them does not defeat caching.
+func catalogETag(version models.CatalogVersion, r *http.Request) string {
+	input := fmt.Sprintf("%d|%s|%s", version.Number, r.URL.Path, r.URL.Query().Encode())
+	digest := sha256.Sum256([]byte(input))
+	return weakETagPrefix + `"` + hex.EncodeToString(digest[:])[:etagLength] + `"`
+}
+
+func notModified(r *http.Request, etag string, modifiedAt time.Time) bool {
+	if ifNoneMatch := r.Header.Get(headerIfNoneMatch); ifNoneMatch != "" {
+		return etagMatches(ifNoneMatch, etag)
+	}
+	if ifModifiedSince := r.Header.Get(headerIfModifiedSince); ifModifiedSince != "" {
+		since, err := http.ParseTime(ifModifiedSince)
+		return err == nil && !modifiedAt.Truncate(time.Second).After(since)
+	}
+	return false
+}
+
+// etagMatches implements the weak comparison If-None-Match calls for, where
+// only the opaque tags have to be equal.
+func etagMatches(ifNoneMatch, etag string) bool {
+	opaque := strings.TrimPrefix(etag, weakETagPrefix)
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, weakETagPrefix) == opaque {
+			return true
+		}
+	}
+	return false
+}
+
+func cacheControlValue(policy HTTPCachePolicy) string {
+	visibility := "public"
+	if policy.Private {
+		visibility = "private"
+	}
+	if policy.MaxAge <= 0 {
+		return visibility + ", no-cache"
+	}
+	return visibility + ", max-age=" + strconv.Itoa(int(policy.MaxAge.Seconds()))
+}
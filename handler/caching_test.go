@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/models"
+	"educabot.com/bookshop/test/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+const pathCachedBooks = "/v2/books"
+
+var (
+	testModifiedAt     = time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	testCatalogVersion = models.CatalogVersion{Number: 7, ModifiedAt: testModifiedAt}
+	testCachePolicy    = HTTPCachePolicy{MaxAge: 30 * time.Second}
+)
+
+func setupCachedRouter(watcher *mocks.MockCatalogWatcher, policy HTTPCachePolicy, status int, calls *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET(pathCachedBooks, Cacheable(watcher, policy), func(ctx *gin.Context) {
+		*calls++
+		if status != http.StatusOK {
+			abortWithErrorEnvelope(ctx, status, "failed")
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	return r
+}
+
+func conditionalGet(router *gin.Engine, target string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCacheable_SetsValidators(t *testing.T) {
+	calls := 0
+	router := setupCachedRouter(mocks.NewMockCatalogWatcher().WithVersion(testCatalogVersion), testCachePolicy, http.StatusOK, &calls)
+
+	rec := conditionalGet(router, pathCachedBooks+"?page=1", nil)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Regexp(t, `^W/"[0-9a-f]{32}"$`, rec.Header().Get(headerETag))
+	require.Equal(t, testModifiedAt.Format(http.TimeFormat), rec.Header().Get(headerLastModified))
+	require.Equal(t, "public, max-age=30", rec.Header().Get(headerCacheControl))
+	require.Equal(t, 1, calls)
+}
+
+func TestCacheable_ETagDependsOnVersionAndQuery(t *testing.T) {
+	calls := 0
+	watcher := mocks.NewMockCatalogWatcher().WithVersion(testCatalogVersion)
+	router := setupCachedRouter(watcher, testCachePolicy, http.StatusOK, &calls)
+
+	base := conditionalGet(router, pathCachedBooks+"?page=1&page_size=5", nil).Header().Get(headerETag)
+	reordered := conditionalGet(router, pathCachedBooks+"?page_size=5&page=1", nil).Header().Get(headerETag)
+	otherQuery := conditionalGet(router, pathCachedBooks+"?page=2&page_size=5", nil).Header().Get(headerETag)
+	watcher.WithVersion(models.CatalogVersion{Number: 8, ModifiedAt: testModifiedAt})
+	otherVersion := conditionalGet(router, pathCachedBooks+"?page=1&page_size=5", nil).Header().Get(headerETag)
+
+	require.Equal(t, base, reordered)
+	require.NotEqual(t, base, otherQuery)
+	require.NotEqual(t, base, otherVersion)
+}
+
+func TestCacheable_IfNoneMatch(t *testing.T) {
+	calls := 0
+	router := setupCachedRouter(mocks.NewMockCatalogWatcher().WithVersion(testCatalogVersion), testCachePolicy, http.StatusOK, &calls)
+	etag := conditionalGet(router, pathCachedBooks, nil).Header().Get(headerETag)
+
+	tests := []struct {
+		name   string
+		header string
+		status int
+	}{
+		{"exact", etag, http.StatusNotModified},
+		{"strong form", strings.TrimPrefix(etag, weakETagPrefix), http.StatusNotModified},
+		{"in list", `"stale", ` + etag, http.StatusNotModified},
+		{"wildcard", "*", http.StatusNotModified},
+		{"stale", `"stale"`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := conditionalGet(router, pathCachedBooks, map[string]string{headerIfNoneMatch: tt.header})
+
+			require.Equal(t, tt.status, rec.Code)
+			require.Equal(t, etag, rec.Header().Get(headerETag))
+		})
+	}
+	require.Equal(t, 2, calls)
+}
+
+func TestCacheable_IfModifiedSince(t *testing.T) {
+	calls := 0
+	router := setupCachedRouter(mocks.NewMockCatalogWatcher().WithVersion(testCatalogVersion), testCachePolicy, http.StatusOK, &calls)
+
+	fresh := conditionalGet(router, pathCachedBooks, map[string]string{headerIfModifiedSince: testModifiedAt.Format(http.TimeFormat)})
+	stale := conditionalGet(router, pathCachedBooks, map[string]string{headerIfModifiedSince: testModifiedAt.Add(-time.Minute).Format(http.TimeFormat)})
+
+	require.Equal(t, http.StatusNotModified, fresh.Code)
+	require.Equal(t, http.StatusOK, stale.Code)
+}
+
+func TestCacheable_SkipsErrorsAndColdCatalog(t *testing.T) {
+	calls := 0
+	failing := setupCachedRouter(mocks.NewMockCatalogWatcher().WithVersion(testCatalogVersion), testCachePolicy, http.StatusBadGateway, &calls)
+	cold := setupCachedRouter(mocks.NewMockCatalogWatcher(), testCachePolicy, http.StatusOK, &calls)
+
+	failed := conditionalGet(failing, pathCachedBooks, nil)
+	uncached := conditionalGet(cold, pathCachedBooks, map[string]string{headerIfNoneMatch: "*"})
+
+	require.Equal(t, http.StatusBadGateway, failed.Code)
+	require.Empty(t, failed.Header().Get(headerETag))
+	require.Empty(t, failed.Header().Get(headerCacheControl))
+	require.Equal(t, http.StatusOK, uncached.Code)
+	require.Empty(t, uncached.Header().Get(headerETag))
+}
+
+func TestCacheable_WriterUnwrapsToConnection(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var deadlineErr error
+	r := gin.New()
+	r.GET(pathCachedBooks, Cacheable(mocks.NewMockCatalogWatcher().WithVersion(testCatalogVersion), testCachePolicy), func(ctx *gin.Context) {
+		deadlineErr = http.NewResponseController(ctx.Writer).SetWriteDeadline(time.Time{})
+		ctx.Status(http.StatusOK)
+	})
+	rec := &deadlineRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, pathCachedBooks, nil))
+
+	require.NoError(t, deadlineErr)
+	require.Equal(t, []time.Time{{}}, rec.deadlines)
+}
+
+func TestCacheControlValue(t *testing.T) {
+	require.Equal(t, "public, max-age=30", cacheControlValue(HTTPCachePolicy{MaxAge: 30 * time.Second}))
+	require.Equal(t, "private, max-age=30", cacheControlValue(HTTPCachePolicy{MaxAge: 30 * time.Second, Private: true}))
+	require.Equal(t, "public, no-cache", cacheControlValue(HTTPCachePolicy{}))
+}
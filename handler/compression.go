@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	EncodingBrotli = "br"
+	EncodingZstd   = "zstd"
+	EncodingGzip   = "gzip"
+
+	headerAcceptEncoding  = "Accept-Encoding"
+	headerContentEncoding = "Content-Encoding"
+	headerContentLength   = "Content-Length"
+	headerContentType     = "Content-Type"
+	headerUpgrade         = "Upgrade"
+
+	encodingIdentity = "identity"
+	encodingAny      = "*"
+
+	// mediaTypeEventStream is never compressed whatever the policy says: each
+	// event has to reach the client as soon as it is flushed.
+	mediaTypeEventStream = "text/event-stream"
+)
+
+type (
+	// CompressionPolicy lists the encodings the server offers, most preferred
+	// first, and which responses are worth compressing. ExcludedContentTypes
+	// holds media types such as "application/zip" or whole families such as
+	// "image/*".
+	CompressionPolicy struct {
+		Encodings            []string
+		MinSize              int
+		ExcludedContentTypes []string
+	}
+
+	resettableEncoder interface {
+		io.WriteCloser
+		Flush() error
+		Reset(w io.Writer)
+	}
+
+	compressor struct {
+		policy CompressionPolicy
+		pools  map[string]*sync.Pool
+	}
+
+	compressWriter struct {
+		gin.ResponseWriter
+		compressor *compressor
+		encoding   string
+
+		buf         bytes.Buffer
+		decided     bool
+		compressing bool
+		encoder     resettableEncoder
+	}
+)
+
+var encoderFactories = map[string]func() resettableEncoder{
+	EncodingBrotli: func() resettableEncoder {
+		return brotli.NewWriterLevel(nil, brotli.DefaultCompression)
+	},
+	EncodingZstd: func() resettableEncoder {
+		encoder, _ := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault), zstd.WithEncoderConcurrency(1))
+		return encoder
+	},
+	EncodingGzip: func() resettableEncoder {
+		return gzip.NewWriter(nil)
+	},
+}
+
+// Compress encodes responses with the best encoding both sides support.
+// Output is buffered until MinSize bytes are known, so small responses are
+// sent as they are, and encoders are pooled per encoding. Because the
+// compressed bytes differ, the encoding is appended to a strong ETag and
+// removed from If-None-Match before later handlers compare validators. Weak
+// ETags are left alone, since they already stand for every encoding.
+func Compress(policy CompressionPolicy) gin.HandlerFunc {
+	c := &compressor{policy: policy, pools: make(map[string]*sync.Pool)}
+	for _, encoding := range policy.Encodings {
+		factory, ok := encoderFactories[encoding]
+		if !ok {
+			continue
+		}
+		c.pools[encoding] = &sync.Pool{New: func() interface{} { return factory() }}
+	}
+
+	return func(ctx *gin.Context) {
+		if ctx.Request.Method == http.MethodHead || ctx.GetHeader(headerUpgrade) != "" {
+			ctx.Next()
+			return
+		}
+		encoding := c.negotiate(ctx.GetHeader(headerAcceptEncoding))
+		if encoding == "" {
+			ctx.Next()
+			return
+		}
+		stripETagEncoding(ctx.Request.Header, encoding)
+
+		writer := &compressWriter{ResponseWriter: ctx.Writer, compressor: c, encoding: encoding}
+		ctx.Writer = writer
+		defer writer.finish()
+		ctx.Next()
+	}
+}
+
+// negotiate picks the acceptable encoding with the highest q-value, breaking
+// ties with the server's preference order.
+func (c *compressor) negotiate(acceptEncoding string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+	weights := make(map[string]float64)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			weight = parsed
+		}
+		weights[strings.ToLower(strings.TrimSpace(name))] = weight
+	}
+
+	best, bestWeight := "", 0.0
+	for _, encoding := range c.policy.Encodings {
+		if _, ok := c.pools[encoding]; !ok {
+			continue
+		}
+		weight, ok := weights[encoding]
+		if !ok {
+			weight, ok = weights[encodingAny]
+		}
+		if ok && weight > bestWeight {
+			best, bestWeight = encoding, weight
+		}
+	}
+	return best
+}
+
+func (c *compressor) excluded(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+	family, _, _ := strings.Cut(mediaType, "/")
+	return mediaType == mediaTypeEventStream ||
+		slices.Contains(c.policy.ExcludedContentTypes, mediaType) ||
+		slices.Contains(c.policy.ExcludedContentTypes, family+"/*")
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.buf.Write(data)
+		if w.buf.Len() >= w.compressor.policy.MinSize {
+			if err := w.decide(); err != nil {
+				return 0, err
+			}
+		}
+		return len(data), nil
+	}
+	if w.compressing {
+		return w.encoder.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.decide()
+	}
+	if w.compressing {
+		w.encoder.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// WriteHeaderNow settles the encoding first so headers sent without a body,
+// such as a 304, still carry Vary.
+func (w *compressWriter) WriteHeaderNow() {
+	if !w.decided {
+		w.decide()
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Written reports true once the handler has produced output, even while it
+// is still held in the buffer.
+func (w *compressWriter) Written() bool {
+	return w.buf.Len() > 0 || w.ResponseWriter.Written()
+}
+
+// Unwrap lets http.ResponseController reach the connection, so streaming
+// handlers can still clear their write deadline.
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *compressWriter) decide() error {
+	w.decided = true
+	header := w.Header()
+	if header.Get(headerContentType) == "" && w.buf.Len() > 0 {
+		header.Set(headerContentType, http.DetectContentType(w.buf.Bytes()))
+	}
+	status := w.Status()
+	w.compressing = w.buf.Len() >= w.compressor.policy.MinSize &&
+		status >= http.StatusOK && status != http.StatusNoContent && status != http.StatusNotModified &&
+		header.Get(headerContentEncoding) == "" &&
+		!w.compressor.excluded(header.Get(headerContentType))
+
+	if !w.compressor.excluded(header.Get(headerContentType)) {
+		header.Add(headerVary, headerAcceptEncoding)
+	}
+	if w.compressing {
+		header.Set(headerContentEncoding, w.encoding)
+		header.Del(headerContentLength)
+		if etag := header.Get(headerETag); strings.HasPrefix(etag, `"`) {
+			header.Set(headerETag, strings.TrimSuffix(etag, `"`)+"-"+w.encoding+`"`)
+		}
+		w.encoder = w.compressor.pools[w.encoding].Get().(resettableEncoder)
+		w.encoder.Reset(w.ResponseWriter)
+		_, err := w.encoder.Write(w.buf.Bytes())
+		w.buf.Reset()
+		return err
+	}
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+func (w *compressWriter) finish() {
+	if !w.decided {
+		if w.buf.Len() == 0 {
+			return
+		}
+		w.decide()
+	}
+	if w.compressing {
+		w.encoder.Close()
+		w.encoder.Reset(io.Discard)
+		w.compressor.pools[w.encoding].Put(w.encoder)
+		w.encoder = nil
+	}
+}
+
+// stripETagEncoding turns the encoding-specific ETags this middleware hands
+// out back into the ones the caching middleware issued.
+func stripETagEncoding(header http.Header, encoding string) {
+	ifNoneMatch := header.Get(headerIfNoneMatch)
+	if ifNoneMatch == "" {
+		return
+	}
+	header.Set(headerIfNoneMatch, strings.ReplaceAll(ifNoneMatch, "-"+encoding+`"`, `"`))
+}
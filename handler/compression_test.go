@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"educabot.com/bookshop/test/mocks"
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	pathCompressed = "/compressed"
+	pathSmall      = "/small"
+	pathImage      = "/image"
+	pathTagged     = "/tagged"
+
+	testStrongETag = "abc123"
+)
+
+var (
+	testCompressionPolicy = CompressionPolicy{
+		Encodings:            []string{EncodingBrotli, EncodingZstd, EncodingGzip},
+		MinSize:              64,
+		ExcludedContentTypes: []string{"image/*", "application/zip"},
+	}
+	largeBody = strings.Repeat(`{"name":"The Fellowship of the Ring"}`, 20)
+)
+
+func setupCompressedRouter(policy CompressionPolicy) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Compress(policy))
+	r.GET(pathCompressed, func(ctx *gin.Context) {
+		ctx.Data(http.StatusOK, "application/json", []byte(largeBody))
+	})
+	r.GET(pathSmall, func(ctx *gin.Context) {
+		ctx.Data(http.StatusOK, "application/json", []byte(`{}`))
+	})
+	r.GET(pathImage, func(ctx *gin.Context) {
+		ctx.Data(http.StatusOK, "image/png", []byte(largeBody))
+	})
+	r.GET(pathTagged, func(ctx *gin.Context) {
+		ctx.Header(headerETag, `"`+testStrongETag+`"`)
+		ctx.Data(http.StatusOK, "application/json", []byte(largeBody))
+	})
+	r.GET(pathCachedBooks, Cacheable(mocks.NewMockCatalogWatcher().WithVersion(testCatalogVersion), testCachePolicy), func(ctx *gin.Context) {
+		ctx.Data(http.StatusOK, "application/json", []byte(largeBody))
+	})
+	return r
+}
+
+func getWithEncoding(router *gin.Engine, target, acceptEncoding string) *httptest.ResponseRecorder {
+	return conditionalGet(router, target, map[string]string{headerAcceptEncoding: acceptEncoding})
+}
+
+func decompress(t *testing.T, encoding string, body []byte) string {
+	var reader io.Reader
+	switch encoding {
+	case EncodingBrotli:
+		reader = brotli.NewReader(bytes.NewReader(body))
+	case EncodingZstd:
+		decoder, err := zstd.NewReader(bytes.NewReader(body))
+		require.NoError(t, err)
+		defer decoder.Close()
+		reader = decoder
+	case EncodingGzip:
+		decoder, err := gzip.NewReader(bytes.NewReader(body))
+		require.NoError(t, err)
+		reader = decoder
+	}
+	decoded, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return string(decoded)
+}
+
+func TestCompress_Negotiation(t *testing.T) {
+	router := setupCompressedRouter(testCompressionPolicy)
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		want           string
+	}{
+		{name: "server preference breaks ties", acceptEncoding: "gzip, zstd, br", want: EncodingBrotli},
+		{name: "client weights win", acceptEncoding: "br;q=0.5, gzip;q=0.9", want: EncodingGzip},
+		{name: "zero weight refuses", acceptEncoding: "br;q=0, zstd", want: EncodingZstd},
+		{name: "wildcard", acceptEncoding: "*", want: EncodingBrotli},
+		{name: "wildcard with exclusion", acceptEncoding: "*, br;q=0", want: EncodingZstd},
+		{name: "unsupported only", acceptEncoding: "deflate", want: ""},
+		{name: "absent", acceptEncoding: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := getWithEncoding(router, pathCompressed, tt.acceptEncoding)
+
+			require.Equal(t, http.StatusOK, rec.Code)
+			require.Equal(t, tt.want, rec.Header().Get(headerContentEncoding))
+			if tt.want == "" {
+				require.Equal(t, largeBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestCompress_RoundTrip(t *testing.T) {
+	router := setupCompressedRouter(testCompressionPolicy)
+	for _, encoding := range testCompressionPolicy.Encodings {
+		t.Run(encoding, func(t *testing.T) {
+			for range 3 {
+				rec := getWithEncoding(router, pathCompressed, encoding)
+
+				require.Equal(t, encoding, rec.Header().Get(headerContentEncoding))
+				require.Equal(t, headerAcceptEncoding, rec.Header().Get(headerVary))
+				require.Empty(t, rec.Header().Get(headerContentLength))
+				require.Less(t, rec.Body.Len(), len(largeBody))
+				require.Equal(t, largeBody, decompress(t, encoding, rec.Body.Bytes()))
+			}
+		})
+	}
+}
+
+func TestCompress_SkipsSmallAndExcludedResponses(t *testing.T) {
+	router := setupCompressedRouter(testCompressionPolicy)
+
+	small := getWithEncoding(router, pathSmall, EncodingGzip)
+	image := getWithEncoding(router, pathImage, EncodingGzip)
+
+	require.Empty(t, small.Header().Get(headerContentEncoding))
+	require.Equal(t, `{}`, small.Body.String())
+	require.Equal(t, headerAcceptEncoding, small.Header().Get(headerVary))
+	require.Empty(t, image.Header().Get(headerContentEncoding))
+	require.Equal(t, largeBody, image.Body.String())
+}
+
+func TestCompress_SkipsUpgradeRequests(t *testing.T) {
+	router := setupCompressedRouter(testCompressionPolicy)
+	upgrade := conditionalGet(router, pathCompressed, map[string]string{
+		headerAcceptEncoding: EncodingGzip,
+		headerUpgrade:        "websocket",
+	})
+
+	require.Empty(t, upgrade.Header().Get(headerContentEncoding))
+	require.Equal(t, largeBody, upgrade.Body.String())
+}
+
+func TestCompress_StrongETagCarriesEncoding(t *testing.T) {
+	router := setupCompressedRouter(testCompressionPolicy)
+
+	rec := getWithEncoding(router, pathTagged, EncodingGzip)
+	stripped := httptest.NewRequest(http.MethodGet, pathTagged, nil)
+	stripped.Header.Set(headerIfNoneMatch, rec.Header().Get(headerETag))
+	stripETagEncoding(stripped.Header, EncodingGzip)
+
+	require.Equal(t, `"`+testStrongETag+`-gzip"`, rec.Header().Get(headerETag))
+	require.Equal(t, `"`+testStrongETag+`"`, stripped.Header.Get(headerIfNoneMatch))
+}
+
+func TestCompress_WeakETagCoversEveryEncoding(t *testing.T) {
+	router := setupCompressedRouter(testCompressionPolicy)
+	plainETag := conditionalGet(router, pathCachedBooks, nil).Header().Get(headerETag)
+
+	rec := getWithEncoding(router, pathCachedBooks, EncodingGzip)
+	etag := rec.Header().Get(headerETag)
+	revalidated := conditionalGet(router, pathCachedBooks, map[string]string{
+		headerAcceptEncoding: EncodingGzip,
+		headerIfNoneMatch:    etag,
+	})
+	crossEncoding := conditionalGet(router, pathCachedBooks, map[string]string{
+		headerAcceptEncoding: EncodingBrotli,
+		headerIfNoneMatch:    etag,
+	})
+
+	require.Equal(t, plainETag, etag)
+	require.Equal(t, http.StatusNotModified, revalidated.Code)
+	require.Empty(t, revalidated.Body.Bytes())
+	require.Equal(t, http.StatusNotModified, crossEncoding.Code)
+}
+
+func TestCompress_FlushesStreamedOutput(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Compress(testCompressionPolicy))
+	r.GET(pathCompressed, func(ctx *gin.Context) {
+		ctx.Header(headerContentType, "application/x-ndjson")
+		ctx.Writer.WriteString(largeBody)
+		ctx.Writer.Flush()
+		ctx.Writer.WriteString("{}\n")
+	})
+
+	rec := getWithEncoding(r, pathCompressed, EncodingGzip)
+
+	require.Equal(t, EncodingGzip, rec.Header().Get(headerContentEncoding))
+	require.Equal(t, largeBody+"{}\n", decompress(t, EncodingGzip, rec.Body.Bytes()))
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	headerOrigin           = "Origin"
+	headerVary             = "Vary"
+	headerAllowOrigin      = "Access-Control-Allow-Origin"
+	headerAllowMethods     = "Access-Control-Allow-Methods"
+	headerAllowHeaders     = "Access-Control-Allow-Headers"
+	headerAllowCredentials = "Access-Control-Allow-Credentials"
+	headerExposeHeaders    = "Access-Control-Expose-Headers"
+	headerMaxAge           = "Access-Control-Max-Age"
+	headerRequestMethod    = "Access-Control-Request-Method"
+	headerRequestHeaders   = "Access-Control-Request-Headers"
+
+	anyOrigin         = "*"
+	wildcardSubdomain = "*."
+	schemeSeparator   = "://"
+
+	corsPreflightRejectedMessage = "CORS preflight rejected"
+	corsPreflightVary            = headerOrigin + ", " + headerRequestMethod + ", " + headerRequestHeaders
+)
+
+// CORSPolicy lists what cross-origin browsers may do. AllowedOrigins holds
+// exact origins such as "https://dash.example.com", subdomain wildcards such
+// as "https://*.example.com" (which do not match the apex domain), or "*".
+type CORSPolicy struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+type originMatcher struct {
+	any       bool
+	exact     map[string]bool
+	wildcards []wildcardOrigin
+}
+
+type wildcardOrigin struct {
+	prefix string
+	suffix string
+}
+
+// CORS answers preflight requests itself and decorates actual cross-origin
+// requests with the headers browsers need. It must run before authentication
+// because browsers send preflights without credentials.
+func CORS(policy CORSPolicy) gin.HandlerFunc {
+	matcher := newOriginMatcher(policy.AllowedOrigins)
+	allowedMethods := strings.Join(policy.AllowedMethods, ", ")
+	allowedHeaders := make([]string, 0, len(policy.AllowedHeaders))
+	for _, header := range policy.AllowedHeaders {
+		allowedHeaders = append(allowedHeaders, http.CanonicalHeaderKey(header))
+	}
+	exposedHeaders := strings.Join(policy.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(policy.MaxAge.Seconds()))
+
+	return func(ctx *gin.Context) {
+		origin := ctx.GetHeader(headerOrigin)
+		if origin == "" {
+			ctx.Next()
+			return
+		}
+		allowed := matcher.matches(origin)
+
+		if ctx.Request.Method == http.MethodOptions && ctx.GetHeader(headerRequestMethod) != "" {
+			ctx.Header(headerVary, corsPreflightVary)
+			requestedHeaders := parseHeaderList(ctx.GetHeader(headerRequestHeaders))
+			if !allowed ||
+				!slices.Contains(policy.AllowedMethods, ctx.GetHeader(headerRequestMethod)) ||
+				!allHeadersAllowed(requestedHeaders, allowedHeaders) {
+				abortWithErrorEnvelope(ctx, http.StatusForbidden, corsPreflightRejectedMessage)
+				return
+			}
+			setAllowOrigin(ctx, policy, matcher, origin)
+			ctx.Header(headerAllowMethods, allowedMethods)
+			if len(requestedHeaders) > 0 {
+				ctx.Header(headerAllowHeaders, strings.Join(requestedHeaders, ", "))
+			}
+			if policy.MaxAge > 0 {
+				ctx.Header(headerMaxAge, maxAge)
+			}
+			ctx.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		ctx.Header(headerVary, headerOrigin)
+		if allowed {
+			setAllowOrigin(ctx, policy, matcher, origin)
+			if exposedHeaders != "" {
+				ctx.Header(headerExposeHeaders, exposedHeaders)
+			}
+		}
+		ctx.Next()
+	}
+}
+
+func setAllowOrigin(ctx *gin.Context, policy CORSPolicy, matcher originMatcher, origin string) {
+	// Credentialed requests must echo the origin; browsers reject "*" there.
+	if matcher.any && !policy.AllowCredentials {
+		ctx.Header(headerAllowOrigin, anyOrigin)
+	} else {
+		ctx.Header(headerAllowOrigin, origin)
+	}
+	if policy.AllowCredentials {
+		ctx.Header(headerAllowCredentials, "true")
+	}
+}
+
+func newOriginMatcher(origins []string) originMatcher {
+	matcher := originMatcher{exact: make(map[string]bool)}
+	for _, origin := range origins {
+		origin = strings.ToLower(origin)
+		switch {
+		case origin == anyOrigin:
+			matcher.any = true
+		case strings.Contains(origin, schemeSeparator+wildcardSubdomain):
+			scheme, host, _ := strings.Cut(origin, schemeSeparator)
+			matcher.wildcards = append(matcher.wildcards, wildcardOrigin{
+				prefix: scheme + schemeSeparator,
+				suffix: strings.TrimPrefix(host, anyOrigin),
+			})
+		default:
+			matcher.exact[origin] = true
+		}
+	}
+	return matcher
+}
+
+func (m originMatcher) matches(origin string) bool {
+	origin = strings.ToLower(origin)
+	if m.any || m.exact[origin] {
+		return true
+	}
+	for _, wildcard := range m.wildcards {
+		if !strings.HasPrefix(origin, wildcard.prefix) || !strings.HasSuffix(origin, wildcard.suffix) {
+			continue
+		}
+		subdomain := strings.TrimSuffix(strings.TrimPrefix(origin, wildcard.prefix), wildcard.suffix)
+		if subdomain != "" && !strings.ContainsAny(subdomain, "/:@") {
+			return true
+		}
+	}
+	return false
+}
+
+func parseHeaderList(raw string) []string {
+	var headers []string
+	for _, header := range strings.Split(raw, ",") {
+		if header = strings.TrimSpace(header); header != "" {
+			headers = append(headers, http.CanonicalHeaderKey(header))
+		}
+	}
+	return headers
+}
+
+func allHeadersAllowed(requested, allowed []string) bool {
+	for _, header := range requested {
+		if !slices.Contains(allowed, header) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	dashboardOrigin = "https://dashboard.example.com"
+	tenantOrigin    = "https://acme.apps.example.com"
+	foreignOrigin   = "https://evil.example.org"
+)
+
+var testCORSPolicy = CORSPolicy{
+	AllowedOrigins:   []string{dashboardOrigin, "https://*.apps.example.com"},
+	AllowedMethods:   []string{http.MethodGet, http.MethodPost},
+	AllowedHeaders:   []string{"Authorization", "Content-Type"},
+	ExposedHeaders:   []string{HeaderRequestID},
+	AllowCredentials: true,
+	MaxAge:           10 * time.Minute,
+}
+
+func setupCORSRouter(policy CORSPolicy) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(CORS(policy))
+	r.GET(pathProtected, func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	return r
+}
+
+func preflight(router *gin.Engine, origin, method, headers string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodOptions, pathProtected, nil)
+	req.Header.Set(headerOrigin, origin)
+	req.Header.Set(headerRequestMethod, method)
+	if headers != "" {
+		req.Header.Set(headerRequestHeaders, headers)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func simpleRequest(router *gin.Engine, origin string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, pathProtected, nil)
+	if origin != "" {
+		req.Header.Set(headerOrigin, origin)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCORS_PreflightAllowed(t *testing.T) {
+	router := setupCORSRouter(testCORSPolicy)
+
+	for _, origin := range []string{dashboardOrigin, tenantOrigin} {
+		rec := preflight(router, origin, http.MethodGet, "authorization, content-type")
+
+		require.Equal(t, http.StatusNoContent, rec.Code, origin)
+		require.Equal(t, origin, rec.Header().Get(headerAllowOrigin))
+		require.Equal(t, "GET, POST", rec.Header().Get(headerAllowMethods))
+		require.Equal(t, "Authorization, Content-Type", rec.Header().Get(headerAllowHeaders))
+		require.Equal(t, "true", rec.Header().Get(headerAllowCredentials))
+		require.Equal(t, "600", rec.Header().Get(headerMaxAge))
+		require.Contains(t, rec.Header().Get(headerVary), headerOrigin)
+	}
+}
+
+func TestCORS_PreflightRejected(t *testing.T) {
+	router := setupCORSRouter(testCORSPolicy)
+
+	tests := []struct {
+		name    string
+		origin  string
+		method  string
+		headers string
+	}{
+		{"unknown origin", foreignOrigin, http.MethodGet, ""},
+		{"apex of wildcard", "https://apps.example.com", http.MethodGet, ""},
+		{"wrong scheme", "http://acme.apps.example.com", http.MethodGet, ""},
+		{"method not allowed", dashboardOrigin, http.MethodDelete, ""},
+		{"header not allowed", dashboardOrigin, http.MethodGet, "X-Custom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := preflight(router, tt.origin, tt.method, tt.headers)
+
+			require.Equal(t, http.StatusForbidden, rec.Code)
+			require.Empty(t, rec.Header().Get(headerAllowOrigin))
+		})
+	}
+}
+
+func TestCORS_SimpleRequest(t *testing.T) {
+	router := setupCORSRouter(testCORSPolicy)
+
+	allowed := simpleRequest(router, dashboardOrigin)
+	foreign := simpleRequest(router, foreignOrigin)
+	sameOrigin := simpleRequest(router, "")
+
+	require.Equal(t, http.StatusOK, allowed.Code)
+	require.Equal(t, dashboardOrigin, allowed.Header().Get(headerAllowOrigin))
+	require.Equal(t, "true", allowed.Header().Get(headerAllowCredentials))
+	require.Equal(t, HeaderRequestID, allowed.Header().Get(headerExposeHeaders))
+	require.Equal(t, headerOrigin, allowed.Header().Get(headerVary))
+	require.Equal(t, http.StatusOK, foreign.Code)
+	require.Empty(t, foreign.Header().Get(headerAllowOrigin))
+	require.Equal(t, http.StatusOK, sameOrigin.Code)
+	require.Empty(t, sameOrigin.Header().Get(headerVary))
+}
+
+func TestCORS_AnyOriginWithoutCredentials(t *testing.T) {
+	router := setupCORSRouter(CORSPolicy{
+		AllowedOrigins: []string{anyOrigin},
+		AllowedMethods: []string{http.MethodGet},
+	})
+
+	rec := simpleRequest(router, foreignOrigin)
+
+	require.Equal(t, anyOrigin, rec.Header().Get(headerAllowOrigin))
+	require.Empty(t, rec.Header().Get(headerAllowCredentials))
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	headerDeprecation = "Deprecation"
+	headerSunset      = "Sunset"
+	headerLink        = "Link"
+)
+
+// Deprecated marks responses as deprecated since deprecatedAt, with the
+// RFC 9745 Deprecation date, and announces when they go away and what
+// replaces them.
+func Deprecated(deprecatedAt, sunset time.Time, successor string) gin.HandlerFunc {
+	deprecationValue := "@" + strconv.FormatInt(deprecatedAt.Unix(), 10)
+	sunsetValue := sunset.UTC().Format(http.TimeFormat)
+	linkValue := fmt.Sprintf(`<%s>; rel="successor-version"`, successor)
+	return func(ctx *gin.Context) {
+		ctx.Header(headerDeprecation, deprecationValue)
+		ctx.Header(headerSunset, sunsetValue)
+		ctx.Header(headerLink, linkValue)
+		ctx.Next()
+	}
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	pathDeprecated     = "/deprecated"
+	testSuccessorPath  = "/v2/books"
+	expectedSunset     = "Fri, 30 Apr 2027 00:00:00 GMT"
+	expectedDeprecated = "@1792368000"
+	expectedLinkHeader = `</v2/books>; rel="successor-version"`
+)
+
+var (
+	testDeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	testSunset       = time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC)
+)
+
+func TestDeprecated_SetsHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET(pathDeprecated, Deprecated(testDeprecatedAt, testSunset, testSuccessorPath), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+	req := httptest.NewRequest(http.MethodGet, pathDeprecated, nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, expectedDeprecated, rec.Header().Get(headerDeprecation))
+	require.Equal(t, expectedSunset, rec.Header().Get(headerSunset))
+	require.Equal(t, expectedLinkHeader, rec.Header().Get(headerLink))
+}
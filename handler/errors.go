@@ -1,21 +1,102 @@
 package handler
 
 import (
+	"context"
 	"errors"
+	"log/slog"
 	"net/http"
 
 	"educabot.com/bookshop/service"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	errorCodeInvalidArgument     = "invalid_argument"
+	errorCodeUnauthenticated     = "unauthenticated"
+	errorCodePermissionDenied    = "permission_denied"
+	errorCodeNotFound            = "not_found"
+	errorCodeRateLimited         = "rate_limited"
+	errorCodeUpstreamUnavailable = "upstream_unavailable"
+	errorCodeInternal            = "internal_error"
+)
+
+// serverErrorMessages replaces the text of errors that are the server's fault,
+// which can carry upstream URLs or connection details, with one fixed message
+// per code.
+var serverErrorMessages = map[string]string{
+	errorCodeUpstreamUnavailable: "book catalog is unavailable",
+	errorCodeInternal:            internalErrorMessage,
+}
+
+type (
+	errorEnvelope struct {
+		Error errorBody `json:"error"`
+	}
+
+	errorBody struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
 )
 
 func mapErrorToHTTPStatus(err error) int {
 	switch {
+	case errors.Is(err, service.ErrInvalidPagination):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrInvalidSort):
+		return http.StatusBadRequest
 	case errors.Is(err, service.ErrNoBooksFound):
 		return http.StatusNotFound
 	case errors.Is(err, service.ErrAuthorNotFound):
 		return http.StatusNotFound
+	case errors.Is(err, service.ErrBookNotFound):
+		return http.StatusNotFound
 	case errors.Is(err, service.ErrFetchingBooks):
 		return http.StatusBadGateway
 	default:
 		return http.StatusInternalServerError
 	}
 }
+
+func mapHTTPStatusToErrorCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return errorCodeInvalidArgument
+	case http.StatusUnauthorized:
+		return errorCodeUnauthenticated
+	case http.StatusForbidden:
+		return errorCodePermissionDenied
+	case http.StatusNotFound:
+		return errorCodeNotFound
+	case http.StatusTooManyRequests:
+		return errorCodeRateLimited
+	case http.StatusBadGateway:
+		return errorCodeUpstreamUnavailable
+	default:
+		return errorCodeInternal
+	}
+}
+
+func abortWithErrorEnvelope(ctx *gin.Context, status int, message string) {
+	ctx.AbortWithStatusJSON(status, errorEnvelope{Error: errorBody{
+		Code:    mapHTTPStatusToErrorCode(status),
+		Message: message,
+	}})
+}
+
+func respondWithErrorEnvelope(ctx *gin.Context, err error) {
+	status := mapErrorToHTTPStatus(err)
+	abortWithErrorEnvelope(ctx, status, clientErrorMessage(ctx.Request.Context(), status, err))
+}
+
+// clientErrorMessage is what a client is told about err. Errors caused by the
+// request describe it as is; server-side errors are logged and replaced by
+// the fixed message for their code.
+func clientErrorMessage(ctx context.Context, status int, err error) string {
+	message, ok := serverErrorMessages[mapHTTPStatusToErrorCode(status)]
+	if !ok {
+		return err.Error()
+	}
+	slog.ErrorContext(ctx, "request failed", "status", status, "error", err)
+	return message
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"educabot.com/bookshop/service"
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+const (
+	queryGraphQLQuery         = "query"
+	queryGraphQLOperationName = "operationName"
+	queryGraphQLVariables     = "variables"
+)
+
+type (
+	graphqlHandler struct {
+		schema graphql.Schema
+		limits GraphQLLimits
+	}
+
+	GraphQLHandler interface {
+		Serve(ctx *gin.Context)
+	}
+
+	graphqlRequest struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+)
+
+func NewGraphQLHandler(metricsService service.MetricsService, booksService service.BooksService, limits GraphQLLimits) (GraphQLHandler, error) {
+	schema, err := newGraphQLSchema(metricsService, booksService)
+	if err != nil {
+		return nil, err
+	}
+	return &graphqlHandler{schema: schema, limits: limits}, nil
+}
+
+func (h *graphqlHandler) Serve(ctx *gin.Context) {
+	request, err := bindGraphQLRequest(ctx, h.limits.MaxQueryBytes)
+	if errors.Is(err, ErrQueryTooLarge) {
+		abortWithGraphQLError(ctx, http.StatusRequestEntityTooLarge, err)
+		return
+	}
+	if err != nil {
+		respondWithGraphQLError(ctx, err)
+		return
+	}
+
+	document, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(request.Query)})})
+	if err != nil {
+		respondWithGraphQLError(ctx, err)
+		return
+	}
+	if err := checkQueryLimits(document, h.limits); err != nil {
+		respondWithGraphQLError(ctx, err)
+		return
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:         h.schema,
+		RequestString:  request.Query,
+		VariableValues: request.Variables,
+		OperationName:  request.OperationName,
+		Context:        ctx.Request.Context(),
+	})
+	ctx.JSON(http.StatusOK, result)
+}
+
+// bindGraphQLRequest reads a GET request's query string or a POST request's
+// JSON body, refusing either once it passes maxBytes.
+func bindGraphQLRequest(ctx *gin.Context, maxBytes int64) (graphqlRequest, error) {
+	if ctx.Request.Method == http.MethodGet {
+		if int64(len(ctx.Request.URL.RawQuery)) > maxBytes {
+			return graphqlRequest{}, ErrQueryTooLarge
+		}
+		request := graphqlRequest{
+			Query:         ctx.Query(queryGraphQLQuery),
+			OperationName: ctx.Query(queryGraphQLOperationName),
+		}
+		if variables := ctx.Query(queryGraphQLVariables); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &request.Variables); err != nil {
+				return graphqlRequest{}, fmt.Errorf("invalid variables: %w", err)
+			}
+		}
+		return request, nil
+	}
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxBytes)
+	var request graphqlRequest
+	err := ctx.ShouldBindJSON(&request)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return graphqlRequest{}, ErrQueryTooLarge
+	}
+	return request, err
+}
+
+func respondWithGraphQLError(ctx *gin.Context, err error) {
+	abortWithGraphQLError(ctx, http.StatusBadRequest, err)
+}
+
+func abortWithGraphQLError(ctx *gin.Context, status int, err error) {
+	ctx.AbortWithStatusJSON(status, graphql.Result{
+		Errors: []gqlerrors.FormattedError{gqlerrors.FormatError(err)},
+	})
+}
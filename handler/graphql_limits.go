@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+var (
+	ErrQueryTooDeep    = errors.New("query exceeds maximum depth")
+	ErrQueryTooComplex = errors.New("query exceeds maximum complexity")
+	ErrQueryTooLarge   = errors.New("query exceeds maximum size")
+)
+
+type (
+	// GraphQLLimits bounds the work one request can ask for. MaxQueryBytes
+	// caps a POST body, or a GET query, before anything is parsed.
+	GraphQLLimits struct {
+		MaxDepth      int
+		MaxComplexity int
+		MaxQueryBytes int64
+	}
+
+	queryAnalyzer struct {
+		fragments map[string]*ast.FragmentDefinition
+		visiting  map[string]bool
+	}
+)
+
+func checkQueryLimits(document *ast.Document, limits GraphQLLimits) error {
+	analyzer := &queryAnalyzer{
+		fragments: make(map[string]*ast.FragmentDefinition),
+		visiting:  make(map[string]bool),
+	}
+	for _, definition := range document.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok {
+			analyzer.fragments[fragment.Name.Value] = fragment
+		}
+	}
+	for _, definition := range document.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		depth, complexity := analyzer.measure(operation.SelectionSet)
+		if limits.MaxDepth > 0 && depth > limits.MaxDepth {
+			return fmt.Errorf("%w: %d > %d", ErrQueryTooDeep, depth, limits.MaxDepth)
+		}
+		if limits.MaxComplexity > 0 && complexity > limits.MaxComplexity {
+			return fmt.Errorf("%w: %d > %d", ErrQueryTooComplex, complexity, limits.MaxComplexity)
+		}
+	}
+	return nil
+}
+
+func (a *queryAnalyzer) measure(selectionSet *ast.SelectionSet) (depth, complexity int) {
+	if selectionSet == nil {
+		return 0, 0
+	}
+	for _, selection := range selectionSet.Selections {
+		var childDepth, childComplexity int
+		switch node := selection.(type) {
+		case *ast.Field:
+			childDepth, childComplexity = a.measure(node.SelectionSet)
+			childDepth++
+			childComplexity++
+		case *ast.InlineFragment:
+			childDepth, childComplexity = a.measure(node.SelectionSet)
+		case *ast.FragmentSpread:
+			childDepth, childComplexity = a.measureFragment(node.Name.Value)
+		}
+		depth = max(depth, childDepth)
+		complexity += childComplexity
+	}
+	return depth, complexity
+}
+
+func (a *queryAnalyzer) measureFragment(name string) (depth, complexity int) {
+	fragment, ok := a.fragments[name]
+	if !ok || a.visiting[name] {
+		return 0, 0
+	}
+	a.visiting[name] = true
+	defer delete(a.visiting, name)
+	return a.measure(fragment.SelectionSet)
+}
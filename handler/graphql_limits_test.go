@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testLimitDepth      = 3
+	testLimitComplexity = 5
+
+	queryWithinLimits      = `{ metrics { cheapestBook { name } } }`
+	queryNestedTooDeep     = `{ metrics { cheapestBook { name { length } } } }`
+	queryFragmentTooDeep   = `{ metrics { ...M } } fragment M on Metrics { cheapestBook { name { length } } }`
+	queryInlineTooComplex  = `{ metrics { ... on Metrics { meanUnitsSold meanUnitsSoldDecimal cheapestBook { name price } } } }`
+	queryRecursiveFragment = `{ metrics { ...A } } fragment A on Metrics { ...B } fragment B on Metrics { ...A meanUnitsSold }`
+)
+
+func parseTestQuery(t *testing.T, query string) *ast.Document {
+	document, err := parser.Parse(parser.ParseParams{Source: query})
+	require.NoError(t, err)
+	return document
+}
+
+func TestCheckQueryLimits_WithinLimits(t *testing.T) {
+	document := parseTestQuery(t, queryWithinLimits)
+
+	err := checkQueryLimits(document, GraphQLLimits{MaxDepth: testLimitDepth, MaxComplexity: testLimitComplexity})
+
+	require.NoError(t, err)
+}
+
+func TestCheckQueryLimits_TooDeep(t *testing.T) {
+	document := parseTestQuery(t, queryNestedTooDeep)
+
+	err := checkQueryLimits(document, GraphQLLimits{MaxDepth: testLimitDepth})
+
+	require.ErrorIs(t, err, ErrQueryTooDeep)
+}
+
+func TestCheckQueryLimits_FragmentTooDeep(t *testing.T) {
+	document := parseTestQuery(t, queryFragmentTooDeep)
+
+	err := checkQueryLimits(document, GraphQLLimits{MaxDepth: testLimitDepth})
+
+	require.ErrorIs(t, err, ErrQueryTooDeep)
+}
+
+func TestCheckQueryLimits_TooComplex(t *testing.T) {
+	document := parseTestQuery(t, queryInlineTooComplex)
+
+	err := checkQueryLimits(document, GraphQLLimits{MaxComplexity: testLimitComplexity})
+
+	require.ErrorIs(t, err, ErrQueryTooComplex)
+}
+
+func TestCheckQueryLimits_RecursiveFragmentTerminates(t *testing.T) {
+	document := parseTestQuery(t, queryRecursiveFragment)
+
+	err := checkQueryLimits(document, GraphQLLimits{MaxDepth: testLimitDepth, MaxComplexity: testLimitComplexity})
+
+	require.NoError(t, err)
+}
+
+func TestCheckQueryLimits_ZeroDisablesLimits(t *testing.T) {
+	document := parseTestQuery(t, queryNestedTooDeep)
+
+	err := checkQueryLimits(document, GraphQLLimits{})
+
+	require.NoError(t, err)
+}
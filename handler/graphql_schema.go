@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"educabot.com/bookshop/models"
+	"educabot.com/bookshop/service"
+	"github.com/graphql-go/graphql"
+)
+
+var errNegativePrice = errors.New("must not be negative")
+
+type graphqlResolver struct {
+	metricsService service.MetricsService
+	booksService   service.BooksService
+}
+
+type metricsRoot struct{}
+
+func newGraphQLSchema(metricsService service.MetricsService, booksService service.BooksService) (graphql.Schema, error) {
+	r := &graphqlResolver{metricsService: metricsService, booksService: booksService}
+
+	bookType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Book",
+		Fields: graphql.Fields{
+			"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: bookField(func(b models.Book) interface{} { return b.ID })},
+			"name":      &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: bookField(func(b models.Book) interface{} { return b.Name })},
+			"author":    &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: bookField(func(b models.Book) interface{} { return b.Author })},
+			"unitsSold": &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: bookField(func(b models.Book) interface{} { return b.UnitsSold })},
+			"price":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: bookField(func(b models.Book) interface{} { return b.Price })},
+		},
+	})
+
+	bookPageType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "BookPage",
+		Fields: graphql.Fields{
+			"items":      &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(bookType))), Resolve: pageField(func(p models.BooksPage) interface{} { return newBooksPageResponse(p).Data })},
+			"page":       &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: pageField(func(p models.BooksPage) interface{} { return p.Page })},
+			"pageSize":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: pageField(func(p models.BooksPage) interface{} { return p.PageSize })},
+			"total":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: pageField(func(p models.BooksPage) interface{} { return p.Total })},
+			"totalPages": &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: pageField(func(p models.BooksPage) interface{} { return p.TotalPages })},
+		},
+	})
+
+	metricsType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Metrics",
+		Fields: graphql.Fields{
+			"meanUnitsSold":        &graphql.Field{Type: graphql.Int, Resolve: r.meanUnitsSold},
+			"meanUnitsSoldDecimal": &graphql.Field{Type: graphql.Float, Resolve: r.meanUnitsSoldDecimal},
+			"cheapestBook":         &graphql.Field{Type: bookType, Resolve: r.cheapestBook},
+			"countByAuthor": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.Int),
+				Args:    graphql.FieldConfigArgument{"author": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)}},
+				Resolve: r.countByAuthor,
+			},
+		},
+	})
+
+	sortFieldEnum := graphql.NewEnum(graphql.EnumConfig{
+		Name: "BookSortField",
+		Values: graphql.EnumValueConfigMap{
+			"ID":         &graphql.EnumValueConfig{Value: models.SortByID},
+			"NAME":       &graphql.EnumValueConfig{Value: models.SortByName},
+			"AUTHOR":     &graphql.EnumValueConfig{Value: models.SortByAuthor},
+			"UNITS_SOLD": &graphql.EnumValueConfig{Value: models.SortByUnitsSold},
+			"PRICE":      &graphql.EnumValueConfig{Value: models.SortByPrice},
+		},
+	})
+
+	sortDirectionEnum := graphql.NewEnum(graphql.EnumConfig{
+		Name: "SortDirection",
+		Values: graphql.EnumValueConfigMap{
+			"ASC":  &graphql.EnumValueConfig{Value: models.SortAscending},
+			"DESC": &graphql.EnumValueConfig{Value: models.SortDescending},
+		},
+	})
+
+	filterInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "BookFilter",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"author":       &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"nameContains": &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"minPrice":     &graphql.InputObjectFieldConfig{Type: graphql.Int},
+			"maxPrice":     &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		},
+	})
+
+	sortInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "BookSort",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"field":     &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(sortFieldEnum)},
+			"direction": &graphql.InputObjectFieldConfig{Type: sortDirectionEnum, DefaultValue: models.SortAscending},
+		},
+	})
+
+	pageInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "PageInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"page":     &graphql.InputObjectFieldConfig{Type: graphql.Int, DefaultValue: defaultPage},
+			"pageSize": &graphql.InputObjectFieldConfig{Type: graphql.Int, DefaultValue: service.DefaultPageSize},
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"books": &graphql.Field{
+				Type: graphql.NewNonNull(bookPageType),
+				Args: graphql.FieldConfigArgument{
+					"filter": &graphql.ArgumentConfig{Type: filterInput},
+					"sort":   &graphql.ArgumentConfig{Type: sortInput},
+					"page":   &graphql.ArgumentConfig{Type: pageInput},
+				},
+				Resolve: r.books,
+			},
+			"book": &graphql.Field{
+				Type:    bookType,
+				Args:    graphql.FieldConfigArgument{"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)}},
+				Resolve: r.book,
+			},
+			"authors": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+				Resolve: r.authors,
+			},
+			"metrics": &graphql.Field{
+				Type:    graphql.NewNonNull(metricsType),
+				Resolve: func(graphql.ResolveParams) (interface{}, error) { return metricsRoot{}, nil },
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
+
+func (r *graphqlResolver) books(p graphql.ResolveParams) (interface{}, error) {
+	query, err := newBooksQuery(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	return r.booksService.ListBooks(p.Context, query)
+}
+
+func (r *graphqlResolver) book(p graphql.ResolveParams) (interface{}, error) {
+	id, _ := p.Args["id"].(int)
+	if id < 0 {
+		return nil, nil
+	}
+	book, err := r.booksService.GetBook(p.Context, uint(id))
+	if errors.Is(err, service.ErrBookNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+func (r *graphqlResolver) authors(p graphql.ResolveParams) (interface{}, error) {
+	return r.booksService.ListAuthors(p.Context)
+}
+
+func (r *graphqlResolver) meanUnitsSold(p graphql.ResolveParams) (interface{}, error) {
+	return r.metricsService.GetMeanUnitsSold(p.Context)
+}
+
+func (r *graphqlResolver) meanUnitsSoldDecimal(p graphql.ResolveParams) (interface{}, error) {
+	return r.metricsService.GetMeanUnitsSoldDecimal(p.Context)
+}
+
+func (r *graphqlResolver) cheapestBook(p graphql.ResolveParams) (interface{}, error) {
+	book, err := r.metricsService.GetCheapestBook(p.Context)
+	if errors.Is(err, service.ErrNoBooksFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+func (r *graphqlResolver) countByAuthor(p graphql.ResolveParams) (interface{}, error) {
+	author, _ := p.Args["author"].(string)
+	count, err := r.metricsService.GetBooksCountByAuthor(p.Context, author)
+	if errors.Is(err, service.ErrAuthorNotFound) || errors.Is(err, service.ErrNoBooksFound) {
+		return 0, nil
+	}
+	return count, err
+}
+
+func newBooksQuery(args map[string]interface{}) (models.BooksQuery, error) {
+	query := models.BooksQuery{
+		Pagination: models.Pagination{Page: defaultPage, PageSize: service.DefaultPageSize},
+	}
+	if filter, ok := args["filter"].(map[string]interface{}); ok {
+		var err error
+		query.Filter.Author, _ = filter["author"].(string)
+		query.Filter.NameContains, _ = filter["nameContains"].(string)
+		if query.Filter.MinPrice, err = optionalPrice(filter, "minPrice"); err != nil {
+			return models.BooksQuery{}, err
+		}
+		if query.Filter.MaxPrice, err = optionalPrice(filter, "maxPrice"); err != nil {
+			return models.BooksQuery{}, err
+		}
+	}
+	if sort, ok := args["sort"].(map[string]interface{}); ok {
+		query.Sort.Field, _ = sort["field"].(models.SortField)
+		query.Sort.Direction, _ = sort["direction"].(models.SortDirection)
+	}
+	if page, ok := args["page"].(map[string]interface{}); ok {
+		if value, ok := page["page"].(int); ok {
+			query.Pagination.Page = value
+		}
+		if value, ok := page["pageSize"].(int); ok {
+			query.Pagination.PageSize = value
+		}
+	}
+	return query, nil
+}
+
+// optionalPrice reads a price filter, which GraphQL can only type as Int, so
+// negative values are rejected here rather than silently ignored.
+func optionalPrice(filter map[string]interface{}, name string) (*uint, error) {
+	price, ok := filter[name].(int)
+	if !ok {
+		return nil, nil
+	}
+	if price < 0 {
+		return nil, fmt.Errorf("filter.%s %w", name, errNegativePrice)
+	}
+	result := uint(price)
+	return &result, nil
+}
+
+func bookField(get func(models.Book) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		book, _ := p.Source.(models.Book)
+		return get(book), nil
+	}
+}
+
+func pageField(get func(models.BooksPage) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		page, _ := p.Source.(models.BooksPage)
+		return get(page), nil
+	}
+}
@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"educabot.com/bookshop/models"
+	"educabot.com/bookshop/service"
+	"educabot.com/bookshop/test/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	pathGraphQL = "/graphql"
+
+	testGraphQLMaxDepth      = 4
+	testGraphQLMaxComplexity = 20
+	testGraphQLMaxQueryBytes = 1024
+	testBookID               = 7
+
+	queryBooksPage     = `{ books(filter: {author: "Tolkien"}, sort: {field: PRICE, direction: DESC}, page: {page: 1, pageSize: 5}) { total items { id name unitsSold } } }`
+	queryBookByID      = `query($id: Int!) { book(id: $id) { id name author price } }`
+	queryAuthors       = `{ authors }`
+	queryMetrics       = `{ metrics { meanUnitsSold meanUnitsSoldDecimal cheapestBook { name price } countByAuthor(author: "Tolkien") } }`
+	queryCountUnknown  = `{ metrics { countByAuthor(author: "Unknown") } }`
+	queryMissingBook   = `{ book(id: 99) { name } }`
+	queryTooDeep       = `{ books { items { author { name { value } } } } }`
+	queryTooComplex    = `{ authors a1: authors a2: authors a3: authors a4: authors a5: authors a6: authors a7: authors a8: authors a9: authors a10: authors a11: authors a12: authors a13: authors a14: authors a15: authors a16: authors a17: authors a18: authors a19: authors a20: authors }`
+	queryInvalidSyntax = `{ books {`
+	queryFetchFailure  = `{ authors }`
+	queryNegativePrice = `{ books(filter: {minPrice: -1}) { total } }`
+)
+
+type graphqlTestResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func setupGraphQLRouter(t *testing.T, metricsSvc service.MetricsService, booksSvc service.BooksService) *gin.Engine {
+	h, err := NewGraphQLHandler(metricsSvc, booksSvc, GraphQLLimits{
+		MaxDepth:      testGraphQLMaxDepth,
+		MaxComplexity: testGraphQLMaxComplexity,
+		MaxQueryBytes: testGraphQLMaxQueryBytes,
+	})
+	require.NoError(t, err)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET(pathGraphQL, h.Serve)
+	r.POST(pathGraphQL, h.Serve)
+	return r
+}
+
+func postGraphQL(t *testing.T, router *gin.Engine, query string, variables map[string]interface{}) (*httptest.ResponseRecorder, graphqlTestResponse) {
+	body, err := json.Marshal(graphqlRequest{Query: query, Variables: variables})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, pathGraphQL, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	var response graphqlTestResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	return rec, response
+}
+
+func TestGraphQL_Books(t *testing.T) {
+	booksSvc := mocks.NewMockBooksService().WithBooksPage(models.BooksPage{
+		Books: []models.Book{{ID: testBookID, Name: testBookLion, UnitsSold: uint(testMeanUnitsSold)}},
+		Total: 1,
+	})
+	router := setupGraphQLRouter(t, mocks.NewMockMetricsService(), booksSvc)
+
+	rec, response := postGraphQL(t, router, queryBooksPage, nil)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Empty(t, response.Errors)
+	var books struct {
+		Total int `json:"total"`
+		Items []struct {
+			ID        int    `json:"id"`
+			Name      string `json:"name"`
+			UnitsSold uint   `json:"unitsSold"`
+		} `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal(response.Data["books"], &books))
+	require.Equal(t, 1, books.Total)
+	require.Equal(t, testBookID, books.Items[0].ID)
+	require.Equal(t, testBookLion, books.Items[0].Name)
+	require.Equal(t, uint(testMeanUnitsSold), books.Items[0].UnitsSold)
+}
+
+func TestGraphQL_BookByID(t *testing.T) {
+	booksSvc := mocks.NewMockBooksService().WithBook(models.Book{ID: testBookID, Name: testBookLion, Author: testAuthorTolkien})
+	router := setupGraphQLRouter(t, mocks.NewMockMetricsService(), booksSvc)
+
+	rec, response := postGraphQL(t, router, queryBookByID, map[string]interface{}{"id": testBookID})
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Empty(t, response.Errors)
+	var book models.Book
+	require.NoError(t, json.Unmarshal(response.Data["book"], &book))
+	require.Equal(t, testBookLion, book.Name)
+	require.Equal(t, testAuthorTolkien, book.Author)
+}
+
+func TestGraphQL_BookNotFoundReturnsNull(t *testing.T) {
+	booksSvc := mocks.NewMockBooksService().WithError(service.ErrBookNotFound)
+	router := setupGraphQLRouter(t, mocks.NewMockMetricsService(), booksSvc)
+
+	rec, response := postGraphQL(t, router, queryMissingBook, nil)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Empty(t, response.Errors)
+	require.JSONEq(t, "null", string(response.Data["book"]))
+}
+
+func TestGraphQL_AuthorsViaGet(t *testing.T) {
+	booksSvc := mocks.NewMockBooksService().WithAuthors([]string{testAuthorTolkien})
+	router := setupGraphQLRouter(t, mocks.NewMockMetricsService(), booksSvc)
+	req := httptest.NewRequest(http.MethodGet, pathGraphQL+"?query="+url.QueryEscape(queryAuthors), nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response graphqlTestResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	var authors []string
+	require.NoError(t, json.Unmarshal(response.Data["authors"], &authors))
+	require.Equal(t, []string{testAuthorTolkien}, authors)
+}
+
+func TestGraphQL_VariablesViaGet(t *testing.T) {
+	booksSvc := mocks.NewMockBooksService().WithBook(models.Book{ID: testBookID, Name: testBookLion, Author: testAuthorTolkien})
+	router := setupGraphQLRouter(t, mocks.NewMockMetricsService(), booksSvc)
+	query := url.Values{queryGraphQLQuery: {queryBookByID}, queryGraphQLVariables: {`{"id": 7}`}}
+	req := httptest.NewRequest(http.MethodGet, pathGraphQL+"?"+query.Encode(), nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response graphqlTestResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Empty(t, response.Errors)
+	var book models.Book
+	require.NoError(t, json.Unmarshal(response.Data["book"], &book))
+	require.Equal(t, testBookLion, book.Name)
+}
+
+func TestGraphQL_InvalidVariablesViaGet(t *testing.T) {
+	router := setupGraphQLRouter(t, mocks.NewMockMetricsService(), mocks.NewMockBooksService())
+	query := url.Values{queryGraphQLQuery: {queryBookByID}, queryGraphQLVariables: {`{"id":`}}
+	req := httptest.NewRequest(http.MethodGet, pathGraphQL+"?"+query.Encode(), nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestGraphQL_Metrics(t *testing.T) {
+	metricsSvc := mocks.NewMockMetricsService().
+		WithMeanUnitsSold(testMeanUnitsSold).
+		WithMeanUnitsSoldDecimal(testDecimalMeanUnitsSold).
+		WithCheapestBook(models.Book{Name: testBookLion, Price: testCheapestPrice}).
+		WithBooksCount(testBooksCount)
+	router := setupGraphQLRouter(t, metricsSvc, mocks.NewMockBooksService())
+
+	rec, response := postGraphQL(t, router, queryMetrics, nil)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Empty(t, response.Errors)
+	var metrics struct {
+		MeanUnitsSold        uint    `json:"meanUnitsSold"`
+		MeanUnitsSoldDecimal float64 `json:"meanUnitsSoldDecimal"`
+		CheapestBook         struct {
+			Name  string `json:"name"`
+			Price uint   `json:"price"`
+		} `json:"cheapestBook"`
+		CountByAuthor uint `json:"countByAuthor"`
+	}
+	require.NoError(t, json.Unmarshal(response.Data["metrics"], &metrics))
+	require.Equal(t, testMeanUnitsSold, metrics.MeanUnitsSold)
+	require.Equal(t, testDecimalMeanUnitsSold, metrics.MeanUnitsSoldDecimal)
+	require.Equal(t, testBookLion, metrics.CheapestBook.Name)
+	require.Equal(t, testCheapestPrice, metrics.CheapestBook.Price)
+	require.Equal(t, testBooksCount, metrics.CountByAuthor)
+}
+
+func TestGraphQL_CountByUnknownAuthorIsZero(t *testing.T) {
+	metricsSvc := mocks.NewMockMetricsService().WithError(service.ErrAuthorNotFound)
+	router := setupGraphQLRouter(t, metricsSvc, mocks.NewMockBooksService())
+
+	rec, response := postGraphQL(t, router, queryCountUnknown, nil)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Empty(t, response.Errors)
+	require.JSONEq(t, `{"countByAuthor":0}`, string(response.Data["metrics"]))
+}
+
+func TestGraphQL_ServiceErrorIsReported(t *testing.T) {
+	booksSvc := mocks.NewMockBooksService().WithError(service.ErrFetchingBooks)
+	router := setupGraphQLRouter(t, mocks.NewMockMetricsService(), booksSvc)
+
+	rec, response := postGraphQL(t, router, queryFetchFailure, nil)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotEmpty(t, response.Errors)
+}
+
+func TestGraphQL_DepthLimitExceeded(t *testing.T) {
+	router := setupGraphQLRouter(t, mocks.NewMockMetricsService(), mocks.NewMockBooksService())
+
+	rec, response := postGraphQL(t, router, queryTooDeep, nil)
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.NotEmpty(t, response.Errors)
+}
+
+func TestGraphQL_ComplexityLimitExceeded(t *testing.T) {
+	router := setupGraphQLRouter(t, mocks.NewMockMetricsService(), mocks.NewMockBooksService())
+
+	rec, response := postGraphQL(t, router, queryTooComplex, nil)
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.NotEmpty(t, response.Errors)
+}
+
+func TestGraphQL_InvalidSyntax(t *testing.T) {
+	router := setupGraphQLRouter(t, mocks.NewMockMetricsService(), mocks.NewMockBooksService())
+
+	rec, response := postGraphQL(t, router, queryInvalidSyntax, nil)
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.NotEmpty(t, response.Errors)
+}
+
+func TestGraphQL_InvalidBody(t *testing.T) {
+	router := setupGraphQLRouter(t, mocks.NewMockMetricsService(), mocks.NewMockBooksService())
+	req := httptest.NewRequest(http.MethodPost, pathGraphQL, bytes.NewReader([]byte(queryInvalidSyntax)))
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestGraphQL_NegativePriceIsRejected(t *testing.T) {
+	router := setupGraphQLRouter(t, mocks.NewMockMetricsService(), mocks.NewMockBooksService())
+
+	rec, response := postGraphQL(t, router, queryNegativePrice, nil)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, response.Errors, 1)
+	require.Equal(t, "filter.minPrice must not be negative", response.Errors[0].Message)
+}
+
+func TestGraphQL_QueryTooLarge(t *testing.T) {
+	router := setupGraphQLRouter(t, mocks.NewMockMetricsService(), mocks.NewMockBooksService())
+	padded := queryAuthors + strings.Repeat(" ", testGraphQLMaxQueryBytes)
+
+	post, _ := postGraphQL(t, router, padded, nil)
+	get := httptest.NewRecorder()
+	router.ServeHTTP(get, httptest.NewRequest(http.MethodGet, pathGraphQL+"?query="+url.QueryEscape(padded), nil))
+
+	require.Equal(t, http.StatusRequestEntityTooLarge, post.Code)
+	require.Equal(t, http.StatusRequestEntityTooLarge, get.Code)
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/http"
+	"runtime"
+	"time"
+
+	"educabot.com/bookshop/health"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	healthHandler struct {
+		registry  *health.Registry
+		startedAt time.Time
+	}
+
+	HealthHandler interface {
+		Liveness(ctx *gin.Context)
+		Readiness(ctx *gin.Context)
+	}
+
+	livenessResponse struct {
+		Status        health.Status `json:"status"`
+		UptimeSeconds float64       `json:"uptime_seconds"`
+		Goroutines    int           `json:"goroutines"`
+	}
+)
+
+func NewHealthHandler(registry *health.Registry, startedAt time.Time) HealthHandler {
+	return &healthHandler{registry: registry, startedAt: startedAt}
+}
+
+func (h *healthHandler) Liveness(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, livenessResponse{
+		Status:        health.StatusUp,
+		UptimeSeconds: time.Since(h.startedAt).Seconds(),
+		Goroutines:    runtime.NumGoroutine(),
+	})
+}
+
+func (h *healthHandler) Readiness(ctx *gin.Context) {
+	report := h.registry.Run(ctx.Request.Context())
+	if report.Status != health.StatusUp {
+		ctx.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
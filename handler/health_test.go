@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/health"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	pathLiveness       = "/healthz"
+	pathReadiness      = "/readyz"
+	testHealthTimeout  = time.Second
+	checkNameUpstream  = "upstream"
+	testUpstreamFailed = "upstream unreachable"
+)
+
+func setupHealthRouter(registry *health.Registry) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewHealthHandler(registry, time.Now())
+	r.GET(pathLiveness, h.Liveness)
+	r.GET(pathReadiness, h.Readiness)
+	return r
+}
+
+func TestLiveness(t *testing.T) {
+	router := setupHealthRouter(health.NewRegistry(testHealthTimeout))
+	req := httptest.NewRequest(http.MethodGet, pathLiveness, nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response livenessResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Equal(t, health.StatusUp, response.Status)
+	require.Positive(t, response.Goroutines)
+}
+
+func TestReadiness_Ready(t *testing.T) {
+	registry := health.NewRegistry(testHealthTimeout)
+	registry.Register(checkNameUpstream, func(context.Context) health.Result {
+		return health.Result{Status: health.StatusUp}
+	})
+	router := setupHealthRouter(registry)
+	req := httptest.NewRequest(http.MethodGet, pathReadiness, nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var report health.Report
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	require.Equal(t, health.StatusUp, report.Checks[checkNameUpstream].Status)
+}
+
+func TestReadiness_NotReady(t *testing.T) {
+	registry := health.NewRegistry(testHealthTimeout)
+	registry.Register(checkNameUpstream, func(context.Context) health.Result {
+		return health.Result{Status: health.StatusDown, Error: testUpstreamFailed}
+	})
+	router := setupHealthRouter(registry)
+	req := httptest.NewRequest(http.MethodGet, pathReadiness, nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	var report health.Report
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	require.Equal(t, health.StatusDown, report.Status)
+	require.Equal(t, testUpstreamFailed, report.Checks[checkNameUpstream].Error)
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const unmatchedRoute = "unmatched"
+
+type RequestObserver interface {
+	ObserveRequest(route, method string, status int, duration time.Duration)
+}
+
+// Instrument reports every request to observer, labelled with the matched route
+// template rather than the raw path so path parameters don't explode cardinality.
+func Instrument(observer RequestObserver) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		observer.ObserveRequest(route, ctx.Request.Method, ctx.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type observation struct {
+	route  string
+	method string
+	status int
+}
+
+type recordingObserver struct {
+	mu           sync.Mutex
+	observations []observation
+}
+
+func (o *recordingObserver) ObserveRequest(route, method string, status int, _ time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.observations = append(o.observations, observation{route: route, method: method, status: status})
+}
+
+func setupInstrumentedRouter(observer RequestObserver) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Instrument(observer))
+	r.GET("/books/count-by-author/:author", func(ctx *gin.Context) {
+		ctx.Status(http.StatusNoContent)
+	})
+	return r
+}
+
+func TestInstrument_UsesRouteTemplate(t *testing.T) {
+	observer := &recordingObserver{}
+	router := setupInstrumentedRouter(observer)
+	req := httptest.NewRequest(http.MethodGet, "/books/count-by-author/Tolkien", nil)
+
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.Equal(t, []observation{{
+		route:  "/books/count-by-author/:author",
+		method: http.MethodGet,
+		status: http.StatusNoContent,
+	}}, observer.observations)
+}
+
+func TestInstrument_UnmatchedRoute(t *testing.T) {
+	observer := &recordingObserver{}
+	router := setupInstrumentedRouter(observer)
+	req := httptest.NewRequest(http.MethodGet, "/missing", nil)
+
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.Equal(t, []observation{{
+		route:  unmatchedRoute,
+		method: http.MethodGet,
+		status: http.StatusNotFound,
+	}}, observer.observations)
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"educabot.com/bookshop/logging"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+const testRequestID = "client-supplied-id"
+
+func setupLoggedRouter(out *bytes.Buffer, status int) (*gin.Engine, *string) {
+	gin.SetMode(gin.TestMode)
+	var seen string
+	r := gin.New()
+	r.Use(RequestID(), LogRequests(logging.NewLogger(out, logging.Config{Level: slog.LevelInfo})))
+	r.GET("/books/:id", func(ctx *gin.Context) {
+		seen = logging.RequestID(ctx.Request.Context())
+		ctx.Status(status)
+	})
+	return r, &seen
+}
+
+func decodeLogRecord(t *testing.T, out *bytes.Buffer) map[string]interface{} {
+	t.Helper()
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimSpace(out.String())), &record))
+	return record
+}
+
+func TestRequestID_PropagatesIncomingHeader(t *testing.T) {
+	var out bytes.Buffer
+	router, seen := setupLoggedRouter(&out, http.StatusOK)
+	req := httptest.NewRequest(http.MethodGet, "/books/1", nil)
+	req.Header.Set(HeaderRequestID, testRequestID)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, testRequestID, rec.Header().Get(HeaderRequestID))
+	require.Equal(t, testRequestID, *seen)
+	record := decodeLogRecord(t, &out)
+	require.Equal(t, testRequestID, record[logging.KeyRequestID])
+}
+
+func TestRequestID_AssignsWhenMissingOrInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{"missing", ""},
+		{"contains spaces", "not a valid id"},
+		{"too long", strings.Repeat("a", maxRequestIDLength+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			router, seen := setupLoggedRouter(&out, http.StatusOK)
+			req := httptest.NewRequest(http.MethodGet, "/books/1", nil)
+			if tt.header != "" {
+				req.Header.Set(HeaderRequestID, tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			assigned := rec.Header().Get(HeaderRequestID)
+			require.Len(t, assigned, 2*requestIDBytes)
+			require.NotEqual(t, tt.header, assigned)
+			require.Equal(t, assigned, *seen)
+		})
+	}
+}
+
+func TestLogRequests_RecordsRequestDetails(t *testing.T) {
+	var out bytes.Buffer
+	router, _ := setupLoggedRouter(&out, http.StatusBadGateway)
+	req := httptest.NewRequest(http.MethodGet, "/books/42", nil)
+
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	record := decodeLogRecord(t, &out)
+	require.Equal(t, slog.LevelError.String(), record[slog.LevelKey])
+	require.Equal(t, http.MethodGet, record["method"])
+	require.Equal(t, "/books/42", record["path"])
+	require.Equal(t, "/books/:id", record["route"])
+	require.EqualValues(t, http.StatusBadGateway, record["status"])
+	require.Contains(t, record, "latency_ms")
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"net/http"
+
+	"educabot.com/bookshop/service"
+	"github.com/gin-gonic/gin"
+)
+
+type metricsHandlerV2 struct {
+	metricsService service.MetricsService
+}
+
+func NewMetricsHandlerV2(metricsService service.MetricsService) MetricsHandler {
+	return &metricsHandlerV2{metricsService: metricsService}
+}
+
+func (h *metricsHandlerV2) GetMeanUnitsSold(ctx *gin.Context) {
+	mean, err := h.metricsService.GetMeanUnitsSoldDecimal(ctx.Request.Context())
+	if err != nil {
+		respondWithErrorEnvelope(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"mean_units_sold": mean})
+}
+
+func (h *metricsHandlerV2) GetCheapestBook(ctx *gin.Context) {
+	book, err := h.metricsService.GetCheapestBook(ctx.Request.Context())
+	if err != nil {
+		respondWithErrorEnvelope(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, book)
+}
+
+func (h *metricsHandlerV2) GetBooksCountByAuthor(ctx *gin.Context) {
+	author := ctx.Param("author")
+
+	count, err := h.metricsService.GetBooksCountByAuthor(ctx.Request.Context(), author)
+	if err != nil {
+		respondWithErrorEnvelope(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"count": count})
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"educabot.com/bookshop/models"
+	"educabot.com/bookshop/service"
+	"educabot.com/bookshop/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+const testDecimalMeanUnitsSold = 53750000.25
+
+func decodeErrorEnvelope(t *testing.T, rec *httptest.ResponseRecorder) errorEnvelope {
+	var envelope errorEnvelope
+	err := json.Unmarshal(rec.Body.Bytes(), &envelope)
+	require.NoError(t, err)
+	return envelope
+}
+
+func TestGetMeanUnitsSoldV2_Success(t *testing.T) {
+	mockSvc := mocks.NewMockMetricsService().WithMeanUnitsSoldDecimal(testDecimalMeanUnitsSold)
+	router := setupRouter(NewMetricsHandlerV2(mockSvc))
+	req := httptest.NewRequest(http.MethodGet, pathMeanUnitsSold, nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response map[string]interface{}
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	require.NoError(t, err)
+	require.Equal(t, testDecimalMeanUnitsSold, response[keyMeanUnitsSold])
+}
+
+func TestGetMeanUnitsSoldV2_NoBooksFound(t *testing.T) {
+	mockSvc := mocks.NewMockMetricsService().WithError(service.ErrNoBooksFound)
+	router := setupRouter(NewMetricsHandlerV2(mockSvc))
+	req := httptest.NewRequest(http.MethodGet, pathMeanUnitsSold, nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusNotFound, rec.Code)
+	require.Equal(t, errorCodeNotFound, decodeErrorEnvelope(t, rec).Error.Code)
+}
+
+func TestGetCheapestBookV2_Success(t *testing.T) {
+	mockSvc := mocks.NewMockMetricsService().WithCheapestBook(models.Book{Name: testBookLion, Price: testCheapestPrice})
+	router := setupRouter(NewMetricsHandlerV2(mockSvc))
+	req := httptest.NewRequest(http.MethodGet, pathCheapest, nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response map[string]interface{}
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	require.NoError(t, err)
+	require.Equal(t, testBookLion, response[keyName])
+}
+
+func TestGetCheapestBookV2_FetchingError(t *testing.T) {
+	mockSvc := mocks.NewMockMetricsService().WithError(service.ErrFetchingBooks)
+	router := setupRouter(NewMetricsHandlerV2(mockSvc))
+	req := httptest.NewRequest(http.MethodGet, pathCheapest, nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusBadGateway, rec.Code)
+	require.Equal(t, errorCodeUpstreamUnavailable, decodeErrorEnvelope(t, rec).Error.Code)
+}
+
+func TestGetCheapestBookV2_HidesUpstreamErrorDetails(t *testing.T) {
+	cause := errors.New("dial tcp upstream.internal:443: connection refused")
+	mockSvc := mocks.NewMockMetricsService().WithError(fmt.Errorf("%w: %w", service.ErrFetchingBooks, cause))
+	router := setupRouter(NewMetricsHandlerV2(mockSvc))
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, pathCheapest, nil))
+
+	envelope := decodeErrorEnvelope(t, rec)
+	require.Equal(t, http.StatusBadGateway, rec.Code)
+	require.Equal(t, serverErrorMessages[errorCodeUpstreamUnavailable], envelope.Error.Message)
+	require.NotContains(t, rec.Body.String(), "upstream.internal")
+}
+
+func TestGetBooksCountByAuthorV2_InvalidAuthorKeepsMessage(t *testing.T) {
+	mockSvc := mocks.NewMockMetricsService().WithError(service.ErrAuthorNotFound)
+	router := setupRouter(NewMetricsHandlerV2(mockSvc))
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, pathCountByAuthor+testAuthorUnknown, nil))
+
+	require.Equal(t, http.StatusNotFound, rec.Code)
+	require.Equal(t, service.ErrAuthorNotFound.Error(), decodeErrorEnvelope(t, rec).Error.Message)
+}
+
+func TestGetBooksCountByAuthorV2_Success(t *testing.T) {
+	mockSvc := mocks.NewMockMetricsService().WithBooksCount(testBooksCount)
+	router := setupRouter(NewMetricsHandlerV2(mockSvc))
+	req := httptest.NewRequest(http.MethodGet, pathCountByAuthor+testAuthorTolkien, nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response map[string]interface{}
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	require.NoError(t, err)
+	require.Equal(t, float64(testBooksCount), response[keyCount])
+}
+
+func TestGetBooksCountByAuthorV2_AuthorNotFound(t *testing.T) {
+	mockSvc := mocks.NewMockMetricsService().WithError(service.ErrAuthorNotFound)
+	router := setupRouter(NewMetricsHandlerV2(mockSvc))
+	req := httptest.NewRequest(http.MethodGet, pathCountByAuthor+testAuthorUnknown, nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusNotFound, rec.Code)
+	require.Equal(t, errorCodeNotFound, decodeErrorEnvelope(t, rec).Error.Code)
+}
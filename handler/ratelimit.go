@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"educabot.com/bookshop/auth"
+	"educabot.com/bookshop/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	headerRetryAfter         = "Retry-After"
+	headerRateLimitLimit     = "RateLimit-Limit"
+	headerRateLimitRemaining = "RateLimit-Remaining"
+	headerRateLimitReset     = "RateLimit-Reset"
+
+	rateLimitedMessage = "rate limit exceeded"
+
+	// preAuthKeyPrefix keeps the IP buckets charged before authentication apart
+	// from the ones unauthenticated clients use afterwards, which have their
+	// own limit.
+	preAuthKeyPrefix = "auth|"
+)
+
+// RateLimitRules maps route templates, as registered with gin, to their own
+// limit. Routes without an entry share the Default bucket of each client.
+type RateLimitRules struct {
+	Default     ratelimit.Limit
+	Routes      map[string]ratelimit.Limit
+	ExemptPaths []string
+}
+
+// RateLimit throttles each client, identified by its authenticated principal
+// or otherwise by its IP as resolved through the router's trusted proxies.
+func RateLimit(store ratelimit.Store, rules RateLimitRules) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if isExemptPath(ctx.Request.URL.Path, rules.ExemptPaths) {
+			ctx.Next()
+			return
+		}
+
+		key := rateLimitClient(ctx)
+		limit := rules.Default
+		if routeLimit, ok := rules.Routes[ctx.FullPath()]; ok {
+			key = ctx.FullPath() + "|" + key
+			limit = routeLimit
+		}
+		if takeToken(ctx, store, key, limit) {
+			ctx.Next()
+		}
+	}
+}
+
+// RateLimitByIP throttles each client IP before authentication runs, so
+// every attempt with bad credentials costs quota and they can't be guessed at
+// an unlimited rate. It belongs in front of Authenticate, with a limit loose
+// enough for several principals sharing one address.
+func RateLimitByIP(store ratelimit.Store, limit ratelimit.Limit, exemptPaths []string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if isExemptPath(ctx.Request.URL.Path, exemptPaths) {
+			ctx.Next()
+			return
+		}
+		if takeToken(ctx, store, preAuthKeyPrefix+"ip:"+ctx.ClientIP(), limit) {
+			ctx.Next()
+		}
+	}
+}
+
+// takeToken charges key one request and reports whether it may go ahead,
+// answering 429 otherwise. If the store fails the request is let through
+// rather than taking the API down with it.
+func takeToken(ctx *gin.Context, store ratelimit.Store, key string, limit ratelimit.Limit) bool {
+	decision, err := store.Take(ctx.Request.Context(), key, limit)
+	if err != nil {
+		slog.WarnContext(ctx.Request.Context(), "rate limit store failed", "error", err)
+		return true
+	}
+
+	ctx.Header(headerRateLimitLimit, strconv.Itoa(decision.Limit))
+	ctx.Header(headerRateLimitRemaining, strconv.Itoa(decision.Remaining))
+	ctx.Header(headerRateLimitReset, strconv.Itoa(ceilSeconds(decision.Reset)))
+	if !decision.Allowed {
+		ctx.Header(headerRetryAfter, strconv.Itoa(max(1, ceilSeconds(decision.RetryAfter))))
+		abortWithErrorEnvelope(ctx, http.StatusTooManyRequests, rateLimitedMessage)
+		return false
+	}
+	return true
+}
+
+// rateLimitClient falls back to the IP for principals without a name, such as
+// JWTs lacking a subject, which would otherwise all share one bucket.
+func rateLimitClient(ctx *gin.Context) string {
+	if principal, ok := auth.PrincipalFromContext(ctx.Request.Context()); ok && principal.Name != "" {
+		return "principal:" + principal.Name
+	}
+	return "ip:" + ctx.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
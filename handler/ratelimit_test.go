@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"educabot.com/bookshop/auth"
+	"educabot.com/bookshop/ratelimit"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	pathLimitedRoute = "/v2/books/cheapest"
+	testClientIP     = "203.0.113.7"
+	testProxyIP      = "10.0.0.1"
+)
+
+var (
+	errStoreDown = errors.New("store down")
+	slowLimit    = ratelimit.Limit{Rate: 0.001, Burst: 2}
+)
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, ratelimit.Limit) (ratelimit.Decision, error) {
+	return ratelimit.Decision{}, errStoreDown
+}
+
+func setupRateLimitedRouter(t *testing.T, store ratelimit.Store, rules RateLimitRules, principal *auth.Principal) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	require.NoError(t, r.SetTrustedProxies([]string{testProxyIP}))
+	if principal != nil {
+		r.Use(func(ctx *gin.Context) {
+			ctx.Request = ctx.Request.WithContext(auth.WithPrincipal(ctx.Request.Context(), *principal))
+		})
+	}
+	r.Use(RateLimit(store, rules))
+	ok := func(ctx *gin.Context) { ctx.Status(http.StatusOK) }
+	r.GET(pathProtected, ok)
+	r.GET(pathLimitedRoute, ok)
+	r.GET(pathLiveness, ok)
+	return r
+}
+
+func requestFrom(router *gin.Engine, path, remoteAddr, forwardedFor string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = remoteAddr + ":12345"
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimit_RejectsOverQuota(t *testing.T) {
+	router := setupRateLimitedRouter(t, ratelimit.NewMemoryStore(), RateLimitRules{Default: slowLimit}, nil)
+
+	first := requestFrom(router, pathProtected, testClientIP, "")
+	requestFrom(router, pathProtected, testClientIP, "")
+	rejected := requestFrom(router, pathProtected, testClientIP, "")
+
+	require.Equal(t, http.StatusOK, first.Code)
+	require.Equal(t, "2", first.Header().Get(headerRateLimitLimit))
+	require.Equal(t, "1", first.Header().Get(headerRateLimitRemaining))
+	require.NotEmpty(t, first.Header().Get(headerRateLimitReset))
+	require.Empty(t, first.Header().Get(headerRetryAfter))
+	require.Equal(t, http.StatusTooManyRequests, rejected.Code)
+	require.Equal(t, "0", rejected.Header().Get(headerRateLimitRemaining))
+	require.NotEmpty(t, rejected.Header().Get(headerRetryAfter))
+	require.Equal(t, errorCodeRateLimited, decodeErrorEnvelope(t, rejected).Error.Code)
+}
+
+func TestRateLimit_KeysByForwardedClientBehindTrustedProxy(t *testing.T) {
+	router := setupRateLimitedRouter(t, ratelimit.NewMemoryStore(), RateLimitRules{Default: slowLimit}, nil)
+
+	requestFrom(router, pathProtected, testProxyIP, testClientIP)
+	requestFrom(router, pathProtected, testProxyIP, testClientIP)
+	sameClient := requestFrom(router, pathProtected, testProxyIP, testClientIP)
+	otherClient := requestFrom(router, pathProtected, testProxyIP, "198.51.100.9")
+	spoofed := requestFrom(router, pathProtected, "192.0.2.1", testClientIP)
+
+	require.Equal(t, http.StatusTooManyRequests, sameClient.Code)
+	require.Equal(t, http.StatusOK, otherClient.Code)
+	require.Equal(t, http.StatusOK, spoofed.Code)
+}
+
+func TestRateLimit_KeysByPrincipal(t *testing.T) {
+	principal := &auth.Principal{Name: testKeyName}
+	router := setupRateLimitedRouter(t, ratelimit.NewMemoryStore(), RateLimitRules{Default: slowLimit}, principal)
+
+	requestFrom(router, pathProtected, testClientIP, "")
+	requestFrom(router, pathProtected, "198.51.100.9", "")
+	rejected := requestFrom(router, pathProtected, "192.0.2.1", "")
+
+	require.Equal(t, http.StatusTooManyRequests, rejected.Code)
+}
+
+func TestRateLimit_PerRouteLimit(t *testing.T) {
+	rules := RateLimitRules{
+		Default: slowLimit,
+		Routes:  map[string]ratelimit.Limit{pathLimitedRoute: {Rate: 0.001, Burst: 1}},
+	}
+	router := setupRateLimitedRouter(t, ratelimit.NewMemoryStore(), rules, nil)
+
+	limitedFirst := requestFrom(router, pathLimitedRoute, testClientIP, "")
+	limitedSecond := requestFrom(router, pathLimitedRoute, testClientIP, "")
+	other := requestFrom(router, pathProtected, testClientIP, "")
+
+	require.Equal(t, http.StatusOK, limitedFirst.Code)
+	require.Equal(t, "1", limitedFirst.Header().Get(headerRateLimitLimit))
+	require.Equal(t, http.StatusTooManyRequests, limitedSecond.Code)
+	require.Equal(t, http.StatusOK, other.Code)
+	require.Equal(t, "1", other.Header().Get(headerRateLimitRemaining))
+}
+
+func TestRateLimit_ExemptPaths(t *testing.T) {
+	rules := RateLimitRules{Default: ratelimit.Limit{Rate: 0.001, Burst: 1}, ExemptPaths: []string{pathLiveness}}
+	router := setupRateLimitedRouter(t, ratelimit.NewMemoryStore(), rules, nil)
+
+	for range 3 {
+		rec := requestFrom(router, pathLiveness, testClientIP, "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Empty(t, rec.Header().Get(headerRateLimitLimit))
+	}
+}
+
+func TestRateLimit_FailsOpenWhenStoreFails(t *testing.T) {
+	router := setupRateLimitedRouter(t, failingStore{}, RateLimitRules{Default: slowLimit}, nil)
+
+	rec := requestFrom(router, pathProtected, testClientIP, "")
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Empty(t, rec.Header().Get(headerRateLimitLimit))
+}
+
+func TestRateLimit_NamelessPrincipalKeysByIP(t *testing.T) {
+	principal := &auth.Principal{Scopes: []string{testScopeRead}}
+	router := setupRateLimitedRouter(t, ratelimit.NewMemoryStore(), RateLimitRules{Default: slowLimit}, principal)
+
+	requestFrom(router, pathProtected, testClientIP, "")
+	requestFrom(router, pathProtected, testClientIP, "")
+	sameClient := requestFrom(router, pathProtected, testClientIP, "")
+	otherClient := requestFrom(router, pathProtected, "198.51.100.9", "")
+
+	require.Equal(t, http.StatusTooManyRequests, sameClient.Code)
+	require.Equal(t, http.StatusOK, otherClient.Code)
+}
+
+func TestRateLimitByIP_CountsFailedAuthentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authenticator, err := auth.NewAPIKeyAuthenticator([]auth.APIKey{
+		{Name: testKeyName, Hash: auth.HashAPIKey(testAPIKey), Scopes: []string{testScopeRead}},
+	})
+	require.NoError(t, err)
+	r := gin.New()
+	r.Use(RateLimitByIP(ratelimit.NewMemoryStore(), slowLimit, []string{pathLiveness}), Authenticate(authenticator, []string{pathLiveness}))
+	r.GET(pathProtected, func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	r.GET(pathLiveness, func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	guess := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, pathProtected, nil)
+		req.RemoteAddr = testClientIP + ":12345"
+		req.Header.Set(auth.HeaderAPIKey, key)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := guess(testUnknownKey)
+	second := guess(testUnknownKey)
+	blocked := guess(testAPIKey)
+	exempt := requestFrom(r, pathLiveness, testClientIP, "")
+
+	require.Equal(t, http.StatusUnauthorized, first.Code)
+	require.Equal(t, http.StatusUnauthorized, second.Code)
+	require.Equal(t, http.StatusTooManyRequests, blocked.Code)
+	require.Equal(t, http.StatusOK, exempt.Code)
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+)
+
+const internalErrorMessage = "internal server error"
+
+type PanicObserver interface {
+	ObservePanic(route string)
+}
+
+// Recover turns a panicking handler into a 500 with the standard error
+// envelope instead of a dropped connection. The panic value and stack are
+// logged with the request context, so the record carries the request ID.
+// It should be the innermost middleware, so the outer ones see the 500.
+func Recover(logger *slog.Logger, observer PanicObserver) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// http.ErrAbortHandler is net/http's way of aborting a response
+			// on purpose; let the server handle it as it normally would.
+			if err, ok := recovered.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(recovered)
+			}
+
+			route := ctx.FullPath()
+			if route == "" {
+				route = unmatchedRoute
+			}
+			observer.ObservePanic(route)
+			logger.ErrorContext(ctx.Request.Context(), "recovered from panic",
+				slog.String("method", ctx.Request.Method),
+				slog.String("path", ctx.Request.URL.Path),
+				slog.String("panic", fmt.Sprint(recovered)),
+				slog.String("stack", string(debug.Stack())),
+			)
+
+			if ctx.Writer.Written() {
+				ctx.Abort()
+				return
+			}
+			abortWithErrorEnvelope(ctx, http.StatusInternalServerError, internalErrorMessage)
+		}()
+		ctx.Next()
+	}
+}
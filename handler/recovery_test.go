@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"educabot.com/bookshop/logging"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+const testPanicRoute = "/books/:id"
+
+type recordingPanicObserver struct {
+	mu     sync.Mutex
+	routes []string
+}
+
+func (o *recordingPanicObserver) ObservePanic(route string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.routes = append(o.routes, route)
+}
+
+func setupRecoveringRouter(out *bytes.Buffer, observer PanicObserver, h gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestID(), Recover(logging.NewLogger(out, logging.Config{Level: slog.LevelInfo}), observer))
+	r.GET(testPanicRoute, h)
+	return r
+}
+
+func TestRecover_ReturnsErrorEnvelope(t *testing.T) {
+	var out bytes.Buffer
+	observer := &recordingPanicObserver{}
+	router := setupRecoveringRouter(&out, observer, func(ctx *gin.Context) {
+		var book *struct{ Name string }
+		ctx.String(http.StatusOK, book.Name)
+	})
+	req := httptest.NewRequest(http.MethodGet, "/books/1", nil)
+	req.Header.Set(HeaderRequestID, testRequestID)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	envelope := decodeErrorEnvelope(t, rec)
+	require.Equal(t, errorCodeInternal, envelope.Error.Code)
+	require.Equal(t, internalErrorMessage, envelope.Error.Message)
+	require.Equal(t, []string{testPanicRoute}, observer.routes)
+	record := decodeLogRecord(t, &out)
+	require.Equal(t, testRequestID, record[logging.KeyRequestID])
+	require.Contains(t, record["panic"], "nil pointer dereference")
+	require.Contains(t, record["stack"], "recovery_test.go")
+}
+
+func TestRecover_PassesThroughWithoutPanic(t *testing.T) {
+	var out bytes.Buffer
+	observer := &recordingPanicObserver{}
+	router := setupRecoveringRouter(&out, observer, func(ctx *gin.Context) {
+		ctx.Status(http.StatusNoContent)
+	})
+	req := httptest.NewRequest(http.MethodGet, "/books/1", nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	require.Empty(t, observer.routes)
+	require.Zero(t, out.Len())
+}
+
+func TestRecover_RepanicsOnAbortHandler(t *testing.T) {
+	var out bytes.Buffer
+	router := setupRecoveringRouter(&out, &recordingPanicObserver{}, func(*gin.Context) {
+		panic(http.ErrAbortHandler)
+	})
+	req := httptest.NewRequest(http.MethodGet, "/books/1", nil)
+
+	require.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	})
+}
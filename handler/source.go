@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+
+	"educabot.com/bookshop/repository"
+	"github.com/gin-gonic/gin"
+)
+
+const HeaderCatalogSource = "X-Catalog-Source"
+
+type sourceWriter struct {
+	gin.ResponseWriter
+	source *repository.Source
+}
+
+// CatalogSource tells clients which book provider served the response, for
+// setups with failover or several providers. Responses that never reached a
+// provider, such as 304s and most errors, carry no header.
+func CatalogSource() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestCtx, source := repository.WithSource(ctx.Request.Context())
+		ctx.Request = ctx.Request.WithContext(requestCtx)
+		ctx.Writer = sourceWriter{ResponseWriter: ctx.Writer, source: source}
+		ctx.Next()
+	}
+}
+
+func (w sourceWriter) WriteHeader(code int) {
+	if provider := w.source.Provider(); provider != "" {
+		w.Header().Set(HeaderCatalogSource, provider)
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap lets http.ResponseController reach the connection, so streaming
+// handlers can still clear their write deadline.
+func (w sourceWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"educabot.com/bookshop/models"
+	"educabot.com/bookshop/repository"
+	"educabot.com/bookshop/test/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+const pathSourcedBooks = "/sourced"
+
+func setupSourceRouter(repo repository.BookRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(CatalogSource())
+	r.GET(pathSourcedBooks, func(ctx *gin.Context) {
+		books, err := repo.GetBooks(ctx.Request.Context())
+		if err != nil {
+			abortWithErrorEnvelope(ctx, http.StatusBadGateway, err.Error())
+			return
+		}
+		ctx.JSON(http.StatusOK, books)
+	})
+	return r
+}
+
+func TestCatalogSource_NamesServingProvider(t *testing.T) {
+	repo := repository.NewFailoverBookRepository([]repository.Provider{
+		{Name: "primary", Priority: 1, Repository: mocks.NewMockBookRepository().WithError(context.DeadlineExceeded)},
+		{Name: "backup", Priority: 2, Repository: mocks.NewMockBookRepository().WithBooks([]models.Book{{ID: 1}})},
+	}, repository.DefaultFailoverCooldown)
+	router := setupSourceRouter(repo)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, pathSourcedBooks, nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "backup", rec.Header().Get(HeaderCatalogSource))
+}
+
+func TestCatalogSource_OmittedWithoutProvider(t *testing.T) {
+	router := setupSourceRouter(mocks.NewMockBookRepository().WithError(context.DeadlineExceeded))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, pathSourcedBooks, nil))
+
+	require.Equal(t, http.StatusBadGateway, rec.Code)
+	require.Empty(t, rec.Header().Get(HeaderCatalogSource))
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"educabot.com/bookshop/service"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	heartbeatInterval = 15 * time.Second
+	heartbeatEvent    = "heartbeat"
+)
+
+type (
+	streamHandler struct {
+		catalogWatcher service.CatalogWatcher
+	}
+
+	StreamHandler interface {
+		StreamCatalog(ctx *gin.Context)
+	}
+)
+
+func NewStreamHandler(catalogWatcher service.CatalogWatcher) StreamHandler {
+	return &streamHandler{catalogWatcher: catalogWatcher}
+}
+
+func (h *streamHandler) StreamCatalog(ctx *gin.Context) {
+	events, unsubscribe := h.catalogWatcher.Subscribe()
+	defer unsubscribe()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	http.NewResponseController(ctx.Writer).SetWriteDeadline(time.Time{})
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	ctx.Writer.WriteHeaderNow()
+	ctx.Writer.Flush()
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			ctx.SSEvent(string(event.Type), event)
+		case <-heartbeat.C:
+			ctx.SSEvent(heartbeatEvent, time.Now().UTC())
+		case <-ctx.Request.Context().Done():
+			return
+		}
+		ctx.Writer.Flush()
+	}
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/logging"
+	"educabot.com/bookshop/models"
+	"educabot.com/bookshop/test/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	pathStream            = "/books/stream"
+	expectedEventAdded    = "event:book_added"
+	expectedEventCheapest = "event:cheapest_book_changed"
+	expectedContentType   = "text/event-stream"
+)
+
+func setupStreamRouter(h StreamHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET(pathStream, h.StreamCatalog)
+	return r
+}
+
+// deadlineRecorder records write deadlines set through
+// http.ResponseController, which a plain ResponseRecorder doesn't support.
+type deadlineRecorder struct {
+	*httptest.ResponseRecorder
+	deadlines []time.Time
+}
+
+func (r *deadlineRecorder) SetWriteDeadline(deadline time.Time) error {
+	r.deadlines = append(r.deadlines, deadline)
+	return nil
+}
+
+// setupMiddlewareStreamRouter mounts the stream behind the middleware main
+// installs on every route, each of which may wrap the response writer.
+func setupMiddlewareStreamRouter(h StreamHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	logger := logging.NewLogger(io.Discard, logging.Config{Level: slog.LevelInfo})
+	r := gin.New()
+	r.Use(
+		RequestID(),
+		LogRequests(logger),
+		Instrument(&recordingObserver{}),
+		Trace(noop.NewTracerProvider(), propagation.TraceContext{}),
+		Compress(CompressionPolicy{Encodings: []string{EncodingGzip}}),
+		Recover(logger, &recordingPanicObserver{}),
+		CatalogSource(),
+	)
+	r.GET(pathStream, h.StreamCatalog)
+	return r
+}
+
+func TestStreamCatalog_WritesEvents(t *testing.T) {
+	book := models.Book{Name: testBookLion, Price: testCheapestPrice}
+	watcher := mocks.NewMockCatalogWatcher().WithEvents([]models.CatalogEvent{
+		{Type: models.EventBookAdded, Book: &book},
+		{Type: models.EventCheapestBookChanged, Book: &book},
+	})
+	router := setupStreamRouter(NewStreamHandler(watcher))
+	req := httptest.NewRequest(http.MethodGet, pathStream, nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, expectedContentType, rec.Result().Header.Get("Content-Type"))
+	require.Contains(t, rec.Body.String(), expectedEventAdded)
+	require.Contains(t, rec.Body.String(), expectedEventCheapest)
+	require.Contains(t, rec.Body.String(), testBookLion)
+	require.True(t, watcher.Unsubscribed)
+}
+
+func TestStreamCatalog_ClosedWatcherEndsStream(t *testing.T) {
+	watcher := mocks.NewMockCatalogWatcher()
+	router := setupStreamRouter(NewStreamHandler(watcher))
+	req := httptest.NewRequest(http.MethodGet, pathStream, nil)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, expectedContentType, rec.Result().Header.Get("Content-Type"))
+	require.Empty(t, rec.Body.String())
+	require.True(t, watcher.Unsubscribed)
+}
+
+func TestStreamCatalog_ClearsWriteDeadlineThroughMiddleware(t *testing.T) {
+	router := setupMiddlewareStreamRouter(NewStreamHandler(mocks.NewMockCatalogWatcher()))
+	rec := &deadlineRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, pathStream, nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, []time.Time{{}}, rec.deadlines)
+}
+
+func TestStreamCatalog_BypassesCompression(t *testing.T) {
+	book := models.Book{Name: testBookLion, Price: testCheapestPrice}
+	watcher := mocks.NewMockCatalogWatcher().WithEvents([]models.CatalogEvent{{Type: models.EventBookAdded, Book: &book}})
+	router := setupMiddlewareStreamRouter(NewStreamHandler(watcher))
+	req := httptest.NewRequest(http.MethodGet, pathStream, nil)
+	req.Header.Set(headerAcceptEncoding, EncodingGzip)
+	rec := &deadlineRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Empty(t, rec.Header().Get(headerContentEncoding))
+	require.Contains(t, rec.Body.String(), expectedEventAdded)
+	require.Equal(t, []time.Time{{}}, rec.deadlines)
+}
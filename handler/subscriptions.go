@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"educabot.com/bookshop/models"
+	"educabot.com/bookshop/service"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	messageSubscribe   = "subscribe"
+	messageUnsubscribe = "unsubscribe"
+	messageUpdate      = "update"
+	messageError       = "error"
+
+	maxMessageSize = 4096
+	writeTimeout   = 10 * time.Second
+	pongTimeout    = 60 * time.Second
+	pingInterval   = 30 * time.Second
+)
+
+var errUnknownMessageType = errors.New("unknown message type")
+
+type (
+	subscriptionsHandler struct {
+		manager  service.SubscriptionManager
+		upgrader websocket.Upgrader
+	}
+
+	SubscriptionsHandler interface {
+		Subscribe(ctx *gin.Context)
+	}
+
+	clientMessage struct {
+		Type string `json:"type"`
+		models.Subscription
+	}
+
+	serverMessage struct {
+		Type string `json:"type"`
+		models.MetricUpdate
+	}
+)
+
+func NewSubscriptionsHandler(manager service.SubscriptionManager) SubscriptionsHandler {
+	return &subscriptionsHandler{manager: manager}
+}
+
+func (h *subscriptionsHandler) Subscribe(ctx *gin.Context) {
+	conn, err := h.upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	session := h.manager.Open()
+	defer session.Close()
+
+	connCtx, cancel := context.WithCancel(ctx.Request.Context())
+	defer cancel()
+	go func() {
+		defer cancel()
+		readClientMessages(connCtx, conn, session)
+	}()
+	writeServerMessages(connCtx, conn, session)
+}
+
+func readClientMessages(ctx context.Context, conn *websocket.Conn, session service.MetricsSession) {
+	conn.SetReadLimit(maxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(pongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongTimeout))
+	})
+	for {
+		var message clientMessage
+		if err := conn.ReadJSON(&message); err != nil {
+			return
+		}
+		switch message.Type {
+		case messageSubscribe:
+			if err := session.Subscribe(ctx, message.Subscription); err != nil {
+				session.Fail(message.ID, err)
+			}
+		case messageUnsubscribe:
+			session.Unsubscribe(message.ID)
+		default:
+			session.Fail(message.ID, errUnknownMessageType)
+		}
+	}
+}
+
+func writeServerMessages(ctx context.Context, conn *websocket.Conn, session service.MetricsSession) {
+	updates := make(chan []models.MetricUpdate)
+	go func() {
+		defer close(updates)
+		for {
+			batch, err := session.Next(ctx)
+			if err != nil {
+				return
+			}
+			select {
+			case updates <- batch:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(pingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case batch, ok := <-updates:
+			if !ok {
+				return
+			}
+			for _, update := range batch {
+				if err := writeMessage(conn, newServerMessage(ctx, update)); err != nil {
+					return
+				}
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func writeMessage(conn *websocket.Conn, message serverMessage) error {
+	if err := conn.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+		return err
+	}
+	return conn.WriteJSON(message)
+}
+
+func newServerMessage(ctx context.Context, update models.MetricUpdate) serverMessage {
+	if update.Err != nil {
+		update.Error = subscriptionErrorMessage(ctx, update.Err)
+	}
+	if update.Error != "" {
+		return serverMessage{Type: messageError, MetricUpdate: update}
+	}
+	return serverMessage{Type: messageUpdate, MetricUpdate: update}
+}
+
+// subscriptionErrorMessage is what a client is told about a failed
+// subscription. Problems with the client's own message are described as is;
+// anything else gets the fixed messages HTTP responses use.
+func subscriptionErrorMessage(ctx context.Context, err error) string {
+	switch {
+	case errors.Is(err, errUnknownMessageType),
+		errors.Is(err, service.ErrMissingSubscriptionID),
+		errors.Is(err, service.ErrUnknownMetric),
+		errors.Is(err, service.ErrMissingAuthor),
+		errors.Is(err, service.ErrTooManySubscriptions):
+		return err.Error()
+	}
+	return clientErrorMessage(ctx, mapErrorToHTTPStatus(err), err)
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName = "educabot.com/bookshop/handler"
+
+	attrHTTPMethod = "http.request.method"
+	attrHTTPRoute  = "http.route"
+	attrHTTPStatus = "http.response.status_code"
+	attrURLPath    = "url.path"
+)
+
+// Trace starts a server span for every request, continuing any trace the
+// caller passed in its traceparent header, and hands the span's context to the
+// handler through the request so services and upstream calls join the trace.
+func Trace(tracerProvider trace.TracerProvider, propagator propagation.TextMapPropagator) gin.HandlerFunc {
+	tracer := tracerProvider.Tracer(tracerName)
+	return func(ctx *gin.Context) {
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		method := ctx.Request.Method
+
+		parent := propagator.Extract(ctx.Request.Context(), propagation.HeaderCarrier(ctx.Request.Header))
+		spanCtx, span := tracer.Start(parent, method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String(attrHTTPMethod, method),
+				attribute.String(attrHTTPRoute, route),
+				attribute.String(attrURLPath, ctx.Request.URL.Path),
+			),
+		)
+		defer span.End()
+
+		ctx.Request = ctx.Request.WithContext(spanCtx)
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		span.SetAttributes(attribute.Int(attrHTTPStatus, status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
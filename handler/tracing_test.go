@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	testTraceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testParentSpan  = "00f067aa0ba902b7"
+)
+
+func setupTracedRouter(status int) (*gin.Engine, *tracetest.SpanRecorder, *trace.SpanContext) {
+	gin.SetMode(gin.TestMode)
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	var handlerSpan trace.SpanContext
+	r := gin.New()
+	r.Use(Trace(provider, propagation.TraceContext{}))
+	r.GET("/books/:id", func(ctx *gin.Context) {
+		handlerSpan = trace.SpanContextFromContext(ctx.Request.Context())
+		ctx.Status(status)
+	})
+	return r, recorder, &handlerSpan
+}
+
+func TestTrace_ContinuesIncomingTrace(t *testing.T) {
+	router, recorder, handlerSpan := setupTracedRouter(http.StatusOK)
+	req := httptest.NewRequest(http.MethodGet, "/books/1", nil)
+	req.Header.Set("traceparent", testTraceparent)
+
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	require.Equal(t, "GET /books/:id", spans[0].Name())
+	require.Equal(t, trace.SpanKindServer, spans[0].SpanKind())
+	require.Equal(t, testTraceID, spans[0].SpanContext().TraceID().String())
+	require.Equal(t, testParentSpan, spans[0].Parent().SpanID().String())
+	require.Equal(t, spans[0].SpanContext(), *handlerSpan)
+}
+
+func TestTrace_StartsNewTraceAndMarksServerErrors(t *testing.T) {
+	router, recorder, _ := setupTracedRouter(http.StatusBadGateway)
+	req := httptest.NewRequest(http.MethodGet, "/books/1", nil)
+
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	require.False(t, spans[0].Parent().IsValid())
+	require.Equal(t, codes.Error, spans[0].Status().Code)
+}
@@ -0,0 +1,189 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"educabot.com/bookshop/repository"
+)
+
+const (
+	detailLastAttempt    = "last_attempt"
+	detailLastSuccess    = "last_success"
+	detailLastSuccessAge = "last_success_age_seconds"
+	detailLastError      = "last_error"
+	detailProbed         = "probed"
+	detailWarm           = "warm"
+	detailInvalidBooks   = "invalid_books"
+	detailProviders      = "providers"
+	detailAvailable      = "available"
+	detailSavedAt        = "saved_at"
+	detailSource         = "source"
+	detailBooks          = "books"
+	detailAge            = "age_seconds"
+	detailDegraded       = "degraded"
+
+	quarantineSampleSize = 5
+
+	errCatalogCold = "catalog snapshot not loaded yet"
+	errFetchTooOld = "last successful fetch is too old"
+	errFetchFailed = "upstream fetch failed: "
+)
+
+type (
+	FetchStatusReporter interface {
+		FetchStatus() repository.FetchStatus
+	}
+
+	// ProviderReporter is optionally implemented by a FetchStatusReporter that
+	// merges several providers, to show how each one did.
+	ProviderReporter interface {
+		Report() []repository.ProviderStatus
+	}
+
+	WarmReporter interface {
+		Warm() bool
+	}
+
+	SnapshotReporter interface {
+		Snapshot() (repository.Snapshot, bool)
+	}
+
+	// QuarantineReporter is implemented by repositories that hold invalid
+	// books back for inspection under the quarantine policy.
+	QuarantineReporter interface {
+		Quarantine() []repository.InvalidBook
+	}
+
+	quarantineDetail struct {
+		Count  int                `json:"count"`
+		Sample []quarantinedEntry `json:"sample,omitempty"`
+	}
+
+	// quarantinedEntry identifies an invalid book without its raw record,
+	// which is only meant for operators reading the logs.
+	quarantinedEntry struct {
+		Index    int                  `json:"index"`
+		ID       string               `json:"id,omitempty"`
+		Problems []repository.Problem `json:"problems"`
+	}
+)
+
+// UpstreamCheck reports on the latest upstream fetches, probing the upstream
+// itself when none succeeded within maxAge. Probes run one at a time and at
+// most once per maxAge, so frequent readiness checks don't hammer a failing
+// upstream. Failures are reported by category, never by their text.
+func UpstreamCheck(bookRepo repository.BookRepository, reporter FetchStatusReporter, maxAge time.Duration) Check {
+	var (
+		probeMu   sync.Mutex
+		lastProbe time.Time
+	)
+	return func(ctx context.Context) Result {
+		status := reporter.FetchStatus()
+		probed := false
+		if stale(status, maxAge) && probeMu.TryLock() {
+			if lastProbe.IsZero() || time.Since(lastProbe) >= maxAge {
+				probed = true
+				lastProbe = time.Now()
+				bookRepo.GetBooks(ctx)
+				status = reporter.FetchStatus()
+			}
+			probeMu.Unlock()
+		}
+
+		result := Result{Status: StatusUp, Details: map[string]interface{}{detailProbed: probed}}
+		if !status.LastAttempt.IsZero() {
+			result.Details[detailLastAttempt] = status.LastAttempt.UTC()
+		}
+		if !status.LastSuccess.IsZero() {
+			result.Details[detailLastSuccess] = status.LastSuccess.UTC()
+			result.Details[detailLastSuccessAge] = time.Since(status.LastSuccess).Seconds()
+		}
+		if status.LastError != nil {
+			result.Details[detailLastError] = repository.ErrorCategory(status.LastError)
+		}
+		if status.InvalidBooks > 0 {
+			result.Details[detailInvalidBooks] = status.InvalidBooks
+		}
+		if providers, ok := reporter.(ProviderReporter); ok {
+			result.Details[detailProviders] = providers.Report()
+		}
+		if stale(status, maxAge) {
+			result.Status = StatusDown
+			result.Error = errFetchTooOld
+			if status.LastError != nil {
+				result.Error = errFetchFailed + repository.ErrorCategory(status.LastError)
+			}
+		}
+		return result
+	}
+}
+
+func stale(status repository.FetchStatus, maxAge time.Duration) bool {
+	return status.LastSuccess.IsZero() || time.Since(status.LastSuccess) > maxAge
+}
+
+func WarmCheck(reporter WarmReporter) Check {
+	return func(context.Context) Result {
+		warm := reporter.Warm()
+		result := Result{Status: StatusUp, Details: map[string]interface{}{detailWarm: warm}}
+		if !warm {
+			result.Status = StatusDown
+			result.Error = errCatalogCold
+		}
+		return result
+	}
+}
+
+// SnapshotCheck describes the persisted catalog. It never fails readiness on
+// its own; pair the upstream check with WithFallback for that.
+func SnapshotCheck(reporter SnapshotReporter) Check {
+	return func(context.Context) Result {
+		snapshot, ok := reporter.Snapshot()
+		result := Result{Status: StatusUp, Details: map[string]interface{}{detailAvailable: ok}}
+		if ok {
+			result.Details[detailSavedAt] = snapshot.SavedAt
+			result.Details[detailSource] = snapshot.Source
+			result.Details[detailBooks] = len(snapshot.Books)
+			result.Details[detailAge] = time.Since(snapshot.SavedAt).Seconds()
+		}
+		return result
+	}
+}
+
+// QuarantineCheck shows how many books each named provider holds in
+// quarantine, with the first few of them. Those books are already left out of
+// the catalog, so it never fails readiness.
+func QuarantineCheck(reporters map[string]QuarantineReporter) Check {
+	return func(context.Context) Result {
+		details := make(map[string]interface{}, len(reporters))
+		for name, reporter := range reporters {
+			quarantine := reporter.Quarantine()
+			detail := quarantineDetail{Count: len(quarantine)}
+			for _, book := range quarantine[:min(len(quarantine), quarantineSampleSize)] {
+				detail.Sample = append(detail.Sample, quarantinedEntry{Index: book.Index, ID: book.ID(), Problems: book.Problems})
+			}
+			details[name] = detail
+		}
+		return Result{Status: StatusUp, Details: details}
+	}
+}
+
+// WithFallback reports check as up, but degraded, while available says
+// something else can serve in its place. The failing check's error and
+// details are kept.
+func WithFallback(check Check, available func() bool) Check {
+	return func(ctx context.Context) Result {
+		result := check(ctx)
+		if result.Status == StatusUp || !available() {
+			return result
+		}
+		if result.Details == nil {
+			result.Details = make(map[string]interface{})
+		}
+		result.Status = StatusUp
+		result.Details[detailDegraded] = true
+		return result
+	}
+}
@@ -0,0 +1,200 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/models"
+	"educabot.com/bookshop/repository"
+	"educabot.com/bookshop/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	validBooksJSON = `[{"id":1,"name":"The Hobbit","author":"J.R.R. Tolkien","units_sold":100,"price":10}]`
+	testMaxAge     = time.Minute
+	staleMaxAge    = time.Nanosecond
+)
+
+func newUpstream(t *testing.T, status int) *repository.HTTPBookRepository {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(validBooksJSON))
+	}))
+	t.Cleanup(server.Close)
+	return repository.NewHTTPBookRepository(server.Client(), server.URL)
+}
+
+func TestUpstreamCheck_FreshFetchSkipsProbe(t *testing.T) {
+	repo := newUpstream(t, http.StatusOK)
+	_, err := repo.GetBooks(context.Background())
+	require.NoError(t, err)
+	check := UpstreamCheck(repo, repo, testMaxAge)
+
+	result := check(context.Background())
+
+	require.Equal(t, StatusUp, result.Status)
+	require.Equal(t, false, result.Details[detailProbed])
+	require.Contains(t, result.Details, detailLastSuccessAge)
+}
+
+func TestUpstreamCheck_ProbesWhenNeverFetched(t *testing.T) {
+	repo := newUpstream(t, http.StatusOK)
+	check := UpstreamCheck(repo, repo, testMaxAge)
+
+	result := check(context.Background())
+
+	require.Equal(t, StatusUp, result.Status)
+	require.Equal(t, true, result.Details[detailProbed])
+}
+
+func TestUpstreamCheck_UnreachableUpstreamIsDown(t *testing.T) {
+	repo := newUpstream(t, http.StatusInternalServerError)
+	check := UpstreamCheck(repo, repo, testMaxAge)
+
+	result := check(context.Background())
+
+	require.Equal(t, StatusDown, result.Status)
+	require.Equal(t, errFetchFailed+repository.ErrorCategoryStatus, result.Error)
+	require.Equal(t, repository.ErrorCategoryStatus, result.Details[detailLastError])
+}
+
+func TestUpstreamCheck_ProbesAtMostOncePerMaxAge(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+	repo := repository.NewHTTPBookRepository(server.Client(), server.URL)
+	check := UpstreamCheck(repo, repo, testMaxAge)
+
+	first := check(context.Background())
+	second := check(context.Background())
+
+	require.Equal(t, int32(1), calls.Load())
+	require.Equal(t, true, first.Details[detailProbed])
+	require.Equal(t, false, second.Details[detailProbed])
+	require.Equal(t, StatusDown, second.Status)
+}
+
+func TestUpstreamCheck_StaleFetchIsDown(t *testing.T) {
+	repo := newUpstream(t, http.StatusOK)
+	check := UpstreamCheck(repo, repo, staleMaxAge)
+
+	result := check(context.Background())
+
+	require.Equal(t, StatusDown, result.Status)
+	require.Equal(t, errFetchTooOld, result.Error)
+}
+
+func TestUpstreamCheck_ReportsInvalidBooks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`[{"id":1,"name":"The Hobbit","author":"J.R.R. Tolkien","units_sold":100,"price":10},{"id":2}]`))
+	}))
+	t.Cleanup(server.Close)
+	repo := repository.NewHTTPBookRepository(server.Client(), server.URL)
+	check := UpstreamCheck(repo, repo, testMaxAge)
+
+	result := check(context.Background())
+
+	require.Equal(t, StatusUp, result.Status)
+	require.Equal(t, 1, result.Details[detailInvalidBooks])
+}
+
+func TestUpstreamCheck_ReportsProviders(t *testing.T) {
+	repo := repository.NewMultiBookRepository([]repository.Provider{
+		{Name: "primary", Priority: 1, Repository: newUpstream(t, http.StatusOK)},
+		{Name: "backup", Priority: 2, Repository: newUpstream(t, http.StatusInternalServerError)},
+	}, repository.MergeByID, repository.PreferPriority)
+	check := UpstreamCheck(repo, repo, testMaxAge)
+
+	result := check(context.Background())
+
+	require.Equal(t, StatusUp, result.Status)
+	providers := result.Details[detailProviders].([]repository.ProviderStatus)
+	require.Len(t, providers, 2)
+	require.Empty(t, providers[0].Error)
+	require.Equal(t, repository.ErrorCategoryStatus, providers[1].Error)
+}
+
+func TestWarmCheck(t *testing.T) {
+	warm := WarmCheck(mocks.NewMockCatalogWatcher().WithWarm(true))
+	cold := WarmCheck(mocks.NewMockCatalogWatcher())
+
+	warmResult := warm(context.Background())
+	coldResult := cold(context.Background())
+
+	require.Equal(t, StatusUp, warmResult.Status)
+	require.Equal(t, StatusDown, coldResult.Status)
+	require.Equal(t, errCatalogCold, coldResult.Error)
+}
+
+type snapshotReporter struct {
+	snapshot repository.Snapshot
+	ok       bool
+}
+
+func (r snapshotReporter) Snapshot() (repository.Snapshot, bool) { return r.snapshot, r.ok }
+
+func TestSnapshotCheck(t *testing.T) {
+	savedAt := time.Now().Add(-time.Hour)
+	available := SnapshotCheck(snapshotReporter{
+		snapshot: repository.Snapshot{SavedAt: savedAt, Source: "primary", Books: []models.Book{{ID: 1}}},
+		ok:       true,
+	})
+	missing := SnapshotCheck(snapshotReporter{})
+
+	availableResult := available(context.Background())
+	missingResult := missing(context.Background())
+
+	require.Equal(t, StatusUp, availableResult.Status)
+	require.Equal(t, savedAt, availableResult.Details[detailSavedAt])
+	require.Equal(t, "primary", availableResult.Details[detailSource])
+	require.Equal(t, 1, availableResult.Details[detailBooks])
+	require.Equal(t, StatusUp, missingResult.Status)
+	require.Equal(t, false, missingResult.Details[detailAvailable])
+}
+
+func TestWithFallback(t *testing.T) {
+	down := func(context.Context) Result { return Result{Status: StatusDown, Error: errFetchTooOld} }
+
+	degraded := WithFallback(down, func() bool { return true })(context.Background())
+	stillDown := WithFallback(down, func() bool { return false })(context.Background())
+
+	require.Equal(t, StatusUp, degraded.Status)
+	require.Equal(t, true, degraded.Details[detailDegraded])
+	require.Equal(t, errFetchTooOld, degraded.Error)
+	require.Equal(t, StatusDown, stillDown.Status)
+}
+
+type quarantineReporter []repository.InvalidBook
+
+func (r quarantineReporter) Quarantine() []repository.InvalidBook { return r }
+
+func TestQuarantineCheck(t *testing.T) {
+	var quarantine quarantineReporter
+	for index := range quarantineSampleSize + 2 {
+		quarantine = append(quarantine, repository.InvalidBook{
+			Index:    index,
+			Raw:      json.RawMessage(fmt.Sprintf(`{"id":%d,"name":""}`, index+1)),
+			Problems: []repository.Problem{{Field: "name", Reason: repository.ReasonMissingField}},
+		})
+	}
+	check := QuarantineCheck(map[string]QuarantineReporter{"primary": quarantine, "backup": quarantineReporter{}})
+
+	result := check(context.Background())
+
+	require.Equal(t, StatusUp, result.Status)
+	primary := result.Details["primary"].(quarantineDetail)
+	require.Equal(t, quarantineSampleSize+2, primary.Count)
+	require.Len(t, primary.Sample, quarantineSampleSize)
+	require.Equal(t, "1", primary.Sample[0].ID)
+	require.Equal(t, quarantineDetail{}, result.Details["backup"])
+}
@@ -0,0 +1,92 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+
+	errCheckTimedOut = "check timed out"
+)
+
+type (
+	Status string
+
+	Result struct {
+		Status  Status                 `json:"status"`
+		Details map[string]interface{} `json:"details,omitempty"`
+		Error   string                 `json:"error,omitempty"`
+	}
+
+	Check func(ctx context.Context) Result
+
+	Report struct {
+		Status Status            `json:"status"`
+		Checks map[string]Result `json:"checks"`
+	}
+
+	Registry struct {
+		timeout time.Duration
+
+		mu     sync.Mutex
+		checks map[string]Check
+	}
+)
+
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{timeout: timeout, checks: make(map[string]Check)}
+}
+
+func (r *Registry) Register(name string, check Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks[name] = check
+}
+
+func (r *Registry) Run(ctx context.Context) Report {
+	r.mu.Lock()
+	checks := make(map[string]Check, len(r.checks))
+	for name, check := range r.checks {
+		checks[name] = check
+	}
+	r.mu.Unlock()
+
+	report := Report{Status: StatusUp, Checks: make(map[string]Result, len(checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := r.runCheck(ctx, check)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if result.Status != StatusUp {
+				report.Status = StatusDown
+			}
+		}()
+	}
+	wg.Wait()
+	return report
+}
+
+func (r *Registry) runCheck(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result := make(chan Result, 1)
+	go func() {
+		result <- check(ctx)
+	}()
+	select {
+	case res := <-result:
+		return res
+	case <-ctx.Done():
+		return Result{Status: StatusDown, Error: errCheckTimedOut}
+	}
+}
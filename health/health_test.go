@@ -0,0 +1,72 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testCheckTimeout = 50 * time.Millisecond
+	checkHealthy     = "healthy"
+	checkFailing     = "failing"
+	checkHanging     = "hanging"
+	testFailure      = "boom"
+)
+
+func healthyCheck(context.Context) Result {
+	return Result{Status: StatusUp}
+}
+
+func failingCheck(context.Context) Result {
+	return Result{Status: StatusDown, Error: testFailure}
+}
+
+func hangingCheck(ctx context.Context) Result {
+	<-ctx.Done()
+	time.Sleep(testCheckTimeout)
+	return Result{Status: StatusUp}
+}
+
+func TestRun_AllChecksUp(t *testing.T) {
+	registry := NewRegistry(testCheckTimeout)
+	registry.Register(checkHealthy, healthyCheck)
+
+	report := registry.Run(context.Background())
+
+	require.Equal(t, StatusUp, report.Status)
+	require.Equal(t, StatusUp, report.Checks[checkHealthy].Status)
+}
+
+func TestRun_NoChecksIsUp(t *testing.T) {
+	registry := NewRegistry(testCheckTimeout)
+
+	report := registry.Run(context.Background())
+
+	require.Equal(t, StatusUp, report.Status)
+	require.Empty(t, report.Checks)
+}
+
+func TestRun_FailingCheckMarksReportDown(t *testing.T) {
+	registry := NewRegistry(testCheckTimeout)
+	registry.Register(checkHealthy, healthyCheck)
+	registry.Register(checkFailing, failingCheck)
+
+	report := registry.Run(context.Background())
+
+	require.Equal(t, StatusDown, report.Status)
+	require.Equal(t, StatusUp, report.Checks[checkHealthy].Status)
+	require.Equal(t, testFailure, report.Checks[checkFailing].Error)
+}
+
+func TestRun_TimedOutCheckIsDown(t *testing.T) {
+	registry := NewRegistry(testCheckTimeout)
+	registry.Register(checkHanging, hangingCheck)
+
+	report := registry.Run(context.Background())
+
+	require.Equal(t, StatusDown, report.Status)
+	require.Equal(t, errCheckTimedOut, report.Checks[checkHanging].Error)
+}
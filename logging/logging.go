@@ -0,0 +1,102 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	KeyRequestID = "request_id"
+	KeyTraceID   = "trace_id"
+	KeySpanID    = "span_id"
+)
+
+type (
+	requestIDKey struct{}
+
+	Config struct {
+		Level slog.Level
+		// SampleEvery keeps one in every SampleEvery records below warning
+		// level. Warnings and errors are never sampled out. Values below 2
+		// disable sampling.
+		SampleEvery int
+	}
+
+	contextHandler struct {
+		next slog.Handler
+	}
+
+	samplingHandler struct {
+		next    slog.Handler
+		every   uint64
+		counter *atomic.Uint64
+	}
+)
+
+// NewLogger returns a JSON logger that adds the request and trace IDs carried
+// by the context to every record logged through the *Context methods.
+func NewLogger(w io.Writer, cfg Config) *slog.Logger {
+	var handler slog.Handler = slog.NewJSONHandler(w, &slog.HandlerOptions{Level: cfg.Level})
+	handler = contextHandler{next: handler}
+	if cfg.SampleEvery > 1 {
+		handler = samplingHandler{next: handler, every: uint64(cfg.SampleEvery), counter: &atomic.Uint64{}}
+	}
+	return slog.New(handler)
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+func (h contextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := RequestID(ctx); requestID != "" {
+		record.AddAttrs(slog.String(KeyRequestID, requestID))
+	}
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+		record.AddAttrs(
+			slog.String(KeyTraceID, spanCtx.TraceID().String()),
+			slog.String(KeySpanID, spanCtx.SpanID().String()),
+		)
+	}
+	return h.next.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{next: h.next.WithGroup(name)}
+}
+
+func (h samplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h samplingHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level < slog.LevelWarn && (h.counter.Add(1)-1)%h.every != 0 {
+		return nil
+	}
+	return h.next.Handle(ctx, record)
+}
+
+func (h samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return samplingHandler{next: h.next.WithAttrs(attrs), every: h.every, counter: h.counter}
+}
+
+func (h samplingHandler) WithGroup(name string) slog.Handler {
+	return samplingHandler{next: h.next.WithGroup(name), every: h.every, counter: h.counter}
+}
@@ -0,0 +1,89 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const testRequestID = "req-123"
+
+func decodeRecords(t *testing.T, out *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestNewLogger_AddsRequestAndTraceIDs(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewLogger(&out, Config{Level: slog.LevelInfo})
+	provider := sdktrace.NewTracerProvider()
+	ctx, span := provider.Tracer("test").Start(WithRequestID(context.Background(), testRequestID), "span")
+	defer span.End()
+
+	logger.InfoContext(ctx, "hello")
+
+	records := decodeRecords(t, &out)
+	require.Len(t, records, 1)
+	require.Equal(t, testRequestID, records[0][KeyRequestID])
+	require.Equal(t, span.SpanContext().TraceID().String(), records[0][KeyTraceID])
+	require.Equal(t, span.SpanContext().SpanID().String(), records[0][KeySpanID])
+}
+
+func TestNewLogger_WithoutContextValues(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewLogger(&out, Config{Level: slog.LevelInfo})
+
+	logger.With("component", "test").Info("hello")
+
+	records := decodeRecords(t, &out)
+	require.Len(t, records, 1)
+	require.NotContains(t, records[0], KeyRequestID)
+	require.NotContains(t, records[0], KeyTraceID)
+	require.Equal(t, "test", records[0]["component"])
+}
+
+func TestNewLogger_RespectsLevel(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewLogger(&out, Config{Level: slog.LevelWarn})
+
+	logger.Info("dropped")
+	logger.Warn("kept")
+
+	records := decodeRecords(t, &out)
+	require.Len(t, records, 1)
+	require.Equal(t, "kept", records[0][slog.MessageKey])
+}
+
+func TestNewLogger_SamplesBelowWarning(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewLogger(&out, Config{Level: slog.LevelInfo, SampleEvery: 3})
+
+	for range 6 {
+		logger.Info("sampled")
+	}
+	logger.With("component", "test").Info("sampled")
+	logger.Error("always")
+
+	records := decodeRecords(t, &out)
+	require.Len(t, records, 4)
+	require.Equal(t, "always", records[3][slog.MessageKey])
+}
+
+func TestRequestID_MissingFromContext(t *testing.T) {
+	require.Empty(t, RequestID(context.Background()))
+}
@@ -0,0 +1,21 @@
+package models
+
+const (
+	EventBookAdded            CatalogEventType = "book_added"
+	EventBookRemoved          CatalogEventType = "book_removed"
+	EventPriceChanged         CatalogEventType = "price_changed"
+	EventUnitsSoldChanged     CatalogEventType = "units_sold_changed"
+	EventMeanUnitsSoldChanged CatalogEventType = "mean_units_sold_changed"
+	EventCheapestBookChanged  CatalogEventType = "cheapest_book_changed"
+)
+
+type (
+	CatalogEventType string
+
+	CatalogEvent struct {
+		Type          CatalogEventType `json:"type"`
+		Book          *Book            `json:"book,omitempty"`
+		Previous      *Book            `json:"previous,omitempty"`
+		MeanUnitsSold *float64         `json:"mean_units_sold,omitempty"`
+	}
+)
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// CatalogSnapshot is the catalog as of one background refresh. Snapshots are
+// shared between readers and must not be modified.
+type CatalogSnapshot struct {
+	Books       []Book
+	Source      string
+	RefreshedAt time.Time
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// CatalogVersion identifies one snapshot of the catalog. Number increases
+// every time the catalog contents change and ModifiedAt records when.
+type CatalogVersion struct {
+	Number     uint64
+	ModifiedAt time.Time
+}
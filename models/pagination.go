@@ -0,0 +1,16 @@
+package models
+
+type (
+	Pagination struct {
+		Page     int
+		PageSize int
+	}
+
+	BooksPage struct {
+		Books      []Book
+		Page       int
+		PageSize   int
+		Total      int
+		TotalPages int
+	}
+)
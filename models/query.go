@@ -0,0 +1,35 @@
+package models
+
+const (
+	SortByID        SortField = "id"
+	SortByName      SortField = "name"
+	SortByAuthor    SortField = "author"
+	SortByUnitsSold SortField = "units_sold"
+	SortByPrice     SortField = "price"
+
+	SortAscending  SortDirection = "asc"
+	SortDescending SortDirection = "desc"
+)
+
+type (
+	SortField     string
+	SortDirection string
+
+	BooksFilter struct {
+		Author       string
+		NameContains string
+		MinPrice     *uint
+		MaxPrice     *uint
+	}
+
+	BooksSort struct {
+		Field     SortField
+		Direction SortDirection
+	}
+
+	BooksQuery struct {
+		Filter     BooksFilter
+		Sort       BooksSort
+		Pagination Pagination
+	}
+)
@@ -0,0 +1,29 @@
+package models
+
+const (
+	MetricMeanUnitsSold Metric = "mean_units_sold"
+	MetricCheapestBook  Metric = "cheapest_book"
+	MetricCountByAuthor Metric = "count_by_author"
+)
+
+type (
+	Metric string
+
+	Subscription struct {
+		ID       string `json:"id"`
+		Metric   Metric `json:"metric"`
+		Author   string `json:"author,omitempty"`
+		MaxPrice *uint  `json:"max_price,omitempty"`
+	}
+
+	// MetricUpdate carries either a value or, in Err, why the subscription
+	// failed. Err is never sent as is; handlers put what the client may see
+	// in Error.
+	MetricUpdate struct {
+		SubscriptionID string      `json:"id"`
+		Metric         Metric      `json:"metric,omitempty"`
+		Value          interface{} `json:"value"`
+		Error          string      `json:"error,omitempty"`
+		Err            error       `json:"-"`
+	}
+)
@@ -0,0 +1,484 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: bookshop/v1/bookshop.proto
+
+package bookshopv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Book struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Author        string                 `protobuf:"bytes,3,opt,name=author,proto3" json:"author,omitempty"`
+	UnitsSold     uint64                 `protobuf:"varint,4,opt,name=units_sold,json=unitsSold,proto3" json:"units_sold,omitempty"`
+	Price         uint64                 `protobuf:"varint,5,opt,name=price,proto3" json:"price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Book) Reset() {
+	*x = Book{}
+	mi := &file_bookshop_v1_bookshop_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Book) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Book) ProtoMessage() {}
+
+func (x *Book) ProtoReflect() protoreflect.Message {
+	mi := &file_bookshop_v1_bookshop_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Book.ProtoReflect.Descriptor instead.
+func (*Book) Descriptor() ([]byte, []int) {
+	return file_bookshop_v1_bookshop_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Book) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Book) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Book) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *Book) GetUnitsSold() uint64 {
+	if x != nil {
+		return x.UnitsSold
+	}
+	return 0
+}
+
+func (x *Book) GetPrice() uint64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+type GetMeanUnitsSoldRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMeanUnitsSoldRequest) Reset() {
+	*x = GetMeanUnitsSoldRequest{}
+	mi := &file_bookshop_v1_bookshop_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMeanUnitsSoldRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMeanUnitsSoldRequest) ProtoMessage() {}
+
+func (x *GetMeanUnitsSoldRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bookshop_v1_bookshop_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMeanUnitsSoldRequest.ProtoReflect.Descriptor instead.
+func (*GetMeanUnitsSoldRequest) Descriptor() ([]byte, []int) {
+	return file_bookshop_v1_bookshop_proto_rawDescGZIP(), []int{1}
+}
+
+type GetMeanUnitsSoldResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MeanUnitsSold uint64                 `protobuf:"varint,1,opt,name=mean_units_sold,json=meanUnitsSold,proto3" json:"mean_units_sold,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMeanUnitsSoldResponse) Reset() {
+	*x = GetMeanUnitsSoldResponse{}
+	mi := &file_bookshop_v1_bookshop_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMeanUnitsSoldResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMeanUnitsSoldResponse) ProtoMessage() {}
+
+func (x *GetMeanUnitsSoldResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bookshop_v1_bookshop_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMeanUnitsSoldResponse.ProtoReflect.Descriptor instead.
+func (*GetMeanUnitsSoldResponse) Descriptor() ([]byte, []int) {
+	return file_bookshop_v1_bookshop_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetMeanUnitsSoldResponse) GetMeanUnitsSold() uint64 {
+	if x != nil {
+		return x.MeanUnitsSold
+	}
+	return 0
+}
+
+type GetCheapestBookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCheapestBookRequest) Reset() {
+	*x = GetCheapestBookRequest{}
+	mi := &file_bookshop_v1_bookshop_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCheapestBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCheapestBookRequest) ProtoMessage() {}
+
+func (x *GetCheapestBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bookshop_v1_bookshop_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCheapestBookRequest.ProtoReflect.Descriptor instead.
+func (*GetCheapestBookRequest) Descriptor() ([]byte, []int) {
+	return file_bookshop_v1_bookshop_proto_rawDescGZIP(), []int{3}
+}
+
+type GetCheapestBookResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Book          *Book                  `protobuf:"bytes,1,opt,name=book,proto3" json:"book,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCheapestBookResponse) Reset() {
+	*x = GetCheapestBookResponse{}
+	mi := &file_bookshop_v1_bookshop_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCheapestBookResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCheapestBookResponse) ProtoMessage() {}
+
+func (x *GetCheapestBookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bookshop_v1_bookshop_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCheapestBookResponse.ProtoReflect.Descriptor instead.
+func (*GetCheapestBookResponse) Descriptor() ([]byte, []int) {
+	return file_bookshop_v1_bookshop_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetCheapestBookResponse) GetBook() *Book {
+	if x != nil {
+		return x.Book
+	}
+	return nil
+}
+
+type GetBooksCountByAuthorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Author        string                 `protobuf:"bytes,1,opt,name=author,proto3" json:"author,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBooksCountByAuthorRequest) Reset() {
+	*x = GetBooksCountByAuthorRequest{}
+	mi := &file_bookshop_v1_bookshop_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBooksCountByAuthorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBooksCountByAuthorRequest) ProtoMessage() {}
+
+func (x *GetBooksCountByAuthorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bookshop_v1_bookshop_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBooksCountByAuthorRequest.ProtoReflect.Descriptor instead.
+func (*GetBooksCountByAuthorRequest) Descriptor() ([]byte, []int) {
+	return file_bookshop_v1_bookshop_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetBooksCountByAuthorRequest) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+type GetBooksCountByAuthorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         uint64                 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBooksCountByAuthorResponse) Reset() {
+	*x = GetBooksCountByAuthorResponse{}
+	mi := &file_bookshop_v1_bookshop_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBooksCountByAuthorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBooksCountByAuthorResponse) ProtoMessage() {}
+
+func (x *GetBooksCountByAuthorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bookshop_v1_bookshop_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBooksCountByAuthorResponse.ProtoReflect.Descriptor instead.
+func (*GetBooksCountByAuthorResponse) Descriptor() ([]byte, []int) {
+	return file_bookshop_v1_bookshop_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetBooksCountByAuthorResponse) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type ListBooksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Author        string                 `protobuf:"bytes,1,opt,name=author,proto3" json:"author,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBooksRequest) Reset() {
+	*x = ListBooksRequest{}
+	mi := &file_bookshop_v1_bookshop_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBooksRequest) ProtoMessage() {}
+
+func (x *ListBooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bookshop_v1_bookshop_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBooksRequest.ProtoReflect.Descriptor instead.
+func (*ListBooksRequest) Descriptor() ([]byte, []int) {
+	return file_bookshop_v1_bookshop_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListBooksRequest) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+var File_bookshop_v1_bookshop_proto protoreflect.FileDescriptor
+
+const file_bookshop_v1_bookshop_proto_rawDesc = "" +
+	"\n" +
+	"\x1abookshop/v1/bookshop.proto\x12\vbookshop.v1\"w\n" +
+	"\x04Book\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06author\x18\x03 \x01(\tR\x06author\x12\x1d\n" +
+	"\n" +
+	"units_sold\x18\x04 \x01(\x04R\tunitsSold\x12\x14\n" +
+	"\x05price\x18\x05 \x01(\x04R\x05price\"\x19\n" +
+	"\x17GetMeanUnitsSoldRequest\"B\n" +
+	"\x18GetMeanUnitsSoldResponse\x12&\n" +
+	"\x0fmean_units_sold\x18\x01 \x01(\x04R\rmeanUnitsSold\"\x18\n" +
+	"\x16GetCheapestBookRequest\"@\n" +
+	"\x17GetCheapestBookResponse\x12%\n" +
+	"\x04book\x18\x01 \x01(\v2\x11.bookshop.v1.BookR\x04book\"6\n" +
+	"\x1cGetBooksCountByAuthorRequest\x12\x16\n" +
+	"\x06author\x18\x01 \x01(\tR\x06author\"5\n" +
+	"\x1dGetBooksCountByAuthorResponse\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x04R\x05count\"*\n" +
+	"\x10ListBooksRequest\x12\x16\n" +
+	"\x06author\x18\x01 \x01(\tR\x06author2\xfd\x02\n" +
+	"\vBookMetrics\x12_\n" +
+	"\x10GetMeanUnitsSold\x12$.bookshop.v1.GetMeanUnitsSoldRequest\x1a%.bookshop.v1.GetMeanUnitsSoldResponse\x12\\\n" +
+	"\x0fGetCheapestBook\x12#.bookshop.v1.GetCheapestBookRequest\x1a$.bookshop.v1.GetCheapestBookResponse\x12n\n" +
+	"\x15GetBooksCountByAuthor\x12).bookshop.v1.GetBooksCountByAuthorRequest\x1a*.bookshop.v1.GetBooksCountByAuthorResponse\x12?\n" +
+	"\tListBooks\x12\x1d.bookshop.v1.ListBooksRequest\x1a\x11.bookshop.v1.Book0\x01B4Z2educabot.com/bookshop/proto/bookshop/v1;bookshopv1b\x06proto3"
+
+var (
+	file_bookshop_v1_bookshop_proto_rawDescOnce sync.Once
+	file_bookshop_v1_bookshop_proto_rawDescData []byte
+)
+
+func file_bookshop_v1_bookshop_proto_rawDescGZIP() []byte {
+	file_bookshop_v1_bookshop_proto_rawDescOnce.Do(func() {
+		file_bookshop_v1_bookshop_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_bookshop_v1_bookshop_proto_rawDesc), len(file_bookshop_v1_bookshop_proto_rawDesc)))
+	})
+	return file_bookshop_v1_bookshop_proto_rawDescData
+}
+
+var file_bookshop_v1_bookshop_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_bookshop_v1_bookshop_proto_goTypes = []any{
+	(*Book)(nil),                          // 0: bookshop.v1.Book
+	(*GetMeanUnitsSoldRequest)(nil),       // 1: bookshop.v1.GetMeanUnitsSoldRequest
+	(*GetMeanUnitsSoldResponse)(nil),      // 2: bookshop.v1.GetMeanUnitsSoldResponse
+	(*GetCheapestBookRequest)(nil),        // 3: bookshop.v1.GetCheapestBookRequest
+	(*GetCheapestBookResponse)(nil),       // 4: bookshop.v1.GetCheapestBookResponse
+	(*GetBooksCountByAuthorRequest)(nil),  // 5: bookshop.v1.GetBooksCountByAuthorRequest
+	(*GetBooksCountByAuthorResponse)(nil), // 6: bookshop.v1.GetBooksCountByAuthorResponse
+	(*ListBooksRequest)(nil),              // 7: bookshop.v1.ListBooksRequest
+}
+var file_bookshop_v1_bookshop_proto_depIdxs = []int32{
+	0, // 0: bookshop.v1.GetCheapestBookResponse.book:type_name -> bookshop.v1.Book
+	1, // 1: bookshop.v1.BookMetrics.GetMeanUnitsSold:input_type -> bookshop.v1.GetMeanUnitsSoldRequest
+	3, // 2: bookshop.v1.BookMetrics.GetCheapestBook:input_type -> bookshop.v1.GetCheapestBookRequest
+	5, // 3: bookshop.v1.BookMetrics.GetBooksCountByAuthor:input_type -> bookshop.v1.GetBooksCountByAuthorRequest
+	7, // 4: bookshop.v1.BookMetrics.ListBooks:input_type -> bookshop.v1.ListBooksRequest
+	2, // 5: bookshop.v1.BookMetrics.GetMeanUnitsSold:output_type -> bookshop.v1.GetMeanUnitsSoldResponse
+	4, // 6: bookshop.v1.BookMetrics.GetCheapestBook:output_type -> bookshop.v1.GetCheapestBookResponse
+	6, // 7: bookshop.v1.BookMetrics.GetBooksCountByAuthor:output_type -> bookshop.v1.GetBooksCountByAuthorResponse
+	0, // 8: bookshop.v1.BookMetrics.ListBooks:output_type -> bookshop.v1.Book
+	5, // [5:9] is the sub-list for method output_type
+	1, // [1:5] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_bookshop_v1_bookshop_proto_init() }
+func file_bookshop_v1_bookshop_proto_init() {
+	if File_bookshop_v1_bookshop_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_bookshop_v1_bookshop_proto_rawDesc), len(file_bookshop_v1_bookshop_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_bookshop_v1_bookshop_proto_goTypes,
+		DependencyIndexes: file_bookshop_v1_bookshop_proto_depIdxs,
+		MessageInfos:      file_bookshop_v1_bookshop_proto_msgTypes,
+	}.Build()
+	File_bookshop_v1_bookshop_proto = out.File
+	file_bookshop_v1_bookshop_proto_goTypes = nil
+	file_bookshop_v1_bookshop_proto_depIdxs = nil
+}
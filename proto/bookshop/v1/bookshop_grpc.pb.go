@@ -0,0 +1,239 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: bookshop/v1/bookshop.proto
+
+package bookshopv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	BookMetrics_GetMeanUnitsSold_FullMethodName      = "/bookshop.v1.BookMetrics/GetMeanUnitsSold"
+	BookMetrics_GetCheapestBook_FullMethodName       = "/bookshop.v1.BookMetrics/GetCheapestBook"
+	BookMetrics_GetBooksCountByAuthor_FullMethodName = "/bookshop.v1.BookMetrics/GetBooksCountByAuthor"
+	BookMetrics_ListBooks_FullMethodName             = "/bookshop.v1.BookMetrics/ListBooks"
+)
+
+// BookMetricsClient is the client API for BookMetrics service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BookMetricsClient interface {
+	GetMeanUnitsSold(ctx context.Context, in *GetMeanUnitsSoldRequest, opts ...grpc.CallOption) (*GetMeanUnitsSoldResponse, error)
+	GetCheapestBook(ctx context.Context, in *GetCheapestBookRequest, opts ...grpc.CallOption) (*GetCheapestBookResponse, error)
+	GetBooksCountByAuthor(ctx context.Context, in *GetBooksCountByAuthorRequest, opts ...grpc.CallOption) (*GetBooksCountByAuthorResponse, error)
+	ListBooks(ctx context.Context, in *ListBooksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Book], error)
+}
+
+type bookMetricsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBookMetricsClient(cc grpc.ClientConnInterface) BookMetricsClient {
+	return &bookMetricsClient{cc}
+}
+
+func (c *bookMetricsClient) GetMeanUnitsSold(ctx context.Context, in *GetMeanUnitsSoldRequest, opts ...grpc.CallOption) (*GetMeanUnitsSoldResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMeanUnitsSoldResponse)
+	err := c.cc.Invoke(ctx, BookMetrics_GetMeanUnitsSold_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookMetricsClient) GetCheapestBook(ctx context.Context, in *GetCheapestBookRequest, opts ...grpc.CallOption) (*GetCheapestBookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCheapestBookResponse)
+	err := c.cc.Invoke(ctx, BookMetrics_GetCheapestBook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookMetricsClient) GetBooksCountByAuthor(ctx context.Context, in *GetBooksCountByAuthorRequest, opts ...grpc.CallOption) (*GetBooksCountByAuthorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBooksCountByAuthorResponse)
+	err := c.cc.Invoke(ctx, BookMetrics_GetBooksCountByAuthor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bookMetricsClient) ListBooks(ctx context.Context, in *ListBooksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Book], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BookMetrics_ServiceDesc.Streams[0], BookMetrics_ListBooks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListBooksRequest, Book]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BookMetrics_ListBooksClient = grpc.ServerStreamingClient[Book]
+
+// BookMetricsServer is the server API for BookMetrics service.
+// All implementations must embed UnimplementedBookMetricsServer
+// for forward compatibility.
+type BookMetricsServer interface {
+	GetMeanUnitsSold(context.Context, *GetMeanUnitsSoldRequest) (*GetMeanUnitsSoldResponse, error)
+	GetCheapestBook(context.Context, *GetCheapestBookRequest) (*GetCheapestBookResponse, error)
+	GetBooksCountByAuthor(context.Context, *GetBooksCountByAuthorRequest) (*GetBooksCountByAuthorResponse, error)
+	ListBooks(*ListBooksRequest, grpc.ServerStreamingServer[Book]) error
+	mustEmbedUnimplementedBookMetricsServer()
+}
+
+// UnimplementedBookMetricsServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedBookMetricsServer struct{}
+
+func (UnimplementedBookMetricsServer) GetMeanUnitsSold(context.Context, *GetMeanUnitsSoldRequest) (*GetMeanUnitsSoldResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetMeanUnitsSold not implemented")
+}
+func (UnimplementedBookMetricsServer) GetCheapestBook(context.Context, *GetCheapestBookRequest) (*GetCheapestBookResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetCheapestBook not implemented")
+}
+func (UnimplementedBookMetricsServer) GetBooksCountByAuthor(context.Context, *GetBooksCountByAuthorRequest) (*GetBooksCountByAuthorResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetBooksCountByAuthor not implemented")
+}
+func (UnimplementedBookMetricsServer) ListBooks(*ListBooksRequest, grpc.ServerStreamingServer[Book]) error {
+	return status.Error(codes.Unimplemented, "method ListBooks not implemented")
+}
+func (UnimplementedBookMetricsServer) mustEmbedUnimplementedBookMetricsServer() {}
+func (UnimplementedBookMetricsServer) testEmbeddedByValue()                     {}
+
+// UnsafeBookMetricsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BookMetricsServer will
+// result in compilation errors.
+type UnsafeBookMetricsServer interface {
+	mustEmbedUnimplementedBookMetricsServer()
+}
+
+func RegisterBookMetricsServer(s grpc.ServiceRegistrar, srv BookMetricsServer) {
+	// If the following call panics, it indicates UnimplementedBookMetricsServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&BookMetrics_ServiceDesc, srv)
+}
+
+func _BookMetrics_GetMeanUnitsSold_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMeanUnitsSoldRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookMetricsServer).GetMeanUnitsSold(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookMetrics_GetMeanUnitsSold_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookMetricsServer).GetMeanUnitsSold(ctx, req.(*GetMeanUnitsSoldRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookMetrics_GetCheapestBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCheapestBookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookMetricsServer).GetCheapestBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookMetrics_GetCheapestBook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookMetricsServer).GetCheapestBook(ctx, req.(*GetCheapestBookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookMetrics_GetBooksCountByAuthor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBooksCountByAuthorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BookMetricsServer).GetBooksCountByAuthor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BookMetrics_GetBooksCountByAuthor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BookMetricsServer).GetBooksCountByAuthor(ctx, req.(*GetBooksCountByAuthorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BookMetrics_ListBooks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListBooksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BookMetricsServer).ListBooks(m, &grpc.GenericServerStream[ListBooksRequest, Book]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BookMetrics_ListBooksServer = grpc.ServerStreamingServer[Book]
+
+// BookMetrics_ServiceDesc is the grpc.ServiceDesc for BookMetrics service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BookMetrics_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "bookshop.v1.BookMetrics",
+	HandlerType: (*BookMetricsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetMeanUnitsSold",
+			Handler:    _BookMetrics_GetMeanUnitsSold_Handler,
+		},
+		{
+			MethodName: "GetCheapestBook",
+			Handler:    _BookMetrics_GetCheapestBook_Handler,
+		},
+		{
+			MethodName: "GetBooksCountByAuthor",
+			Handler:    _BookMetrics_GetBooksCountByAuthor_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListBooks",
+			Handler:       _BookMetrics_ListBooks_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "bookshop/v1/bookshop.proto",
+}
@@ -0,0 +1,116 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+type (
+	// Limit is a token bucket that refills Rate tokens per second up to Burst.
+	Limit struct {
+		Rate  float64
+		Burst int
+	}
+
+	Decision struct {
+		Allowed   bool
+		Limit     int
+		Remaining int
+		// RetryAfter is how long until the next token is available. It is
+		// zero when the request was allowed.
+		RetryAfter time.Duration
+		// Reset is how long until the bucket is full again.
+		Reset time.Duration
+	}
+
+	// Store keeps one bucket per key. MemoryStore is the in-process
+	// implementation; a shared store lets several replicas enforce one quota.
+	Store interface {
+		Take(ctx context.Context, key string, limit Limit) (Decision, error)
+	}
+
+	MemoryStore struct {
+		now func() time.Time
+
+		mu      sync.Mutex
+		buckets map[string]*bucket
+	}
+
+	bucket struct {
+		tokens   float64
+		updated  time.Time
+		refilled time.Time
+	}
+)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{now: time.Now, buckets: make(map[string]*bucket)}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Decision, error) {
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		s.buckets[key] = b
+	}
+	b.refill(now, limit)
+
+	decision := Decision{Limit: limit.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = durationFor(1-b.tokens, limit.Rate)
+	}
+	decision.Remaining = int(math.Floor(b.tokens))
+	decision.Reset = durationFor(float64(limit.Burst)-b.tokens, limit.Rate)
+	b.refilled = now.Add(decision.Reset)
+	return decision, nil
+}
+
+// Run evicts buckets that have refilled completely, since a fresh bucket
+// behaves the same, until ctx is done.
+func (s *MemoryStore) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.evictFull()
+		}
+	}
+}
+
+func (s *MemoryStore) evictFull() {
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, b := range s.buckets {
+		if !now.Before(b.refilled) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func (b *bucket) refill(now time.Time, limit Limit) {
+	elapsed := now.Sub(b.updated).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed*limit.Rate)
+		b.updated = now
+	}
+}
+
+func durationFor(tokens, rate float64) time.Duration {
+	if tokens <= 0 || rate <= 0 {
+		return 0
+	}
+	return time.Duration(tokens / rate * float64(time.Second))
+}
@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testKey = "ip:10.0.0.1"
+
+var testLimit = Limit{Rate: 2, Burst: 3}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestStore() (*MemoryStore, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	store := NewMemoryStore()
+	store.now = clock.Now
+	return store, clock
+}
+
+func take(t *testing.T, store *MemoryStore, key string) Decision {
+	t.Helper()
+	decision, err := store.Take(context.Background(), key, testLimit)
+	require.NoError(t, err)
+	return decision
+}
+
+func TestMemoryStore_AllowsBurstThenRejects(t *testing.T) {
+	store, _ := newTestStore()
+
+	for remaining := testLimit.Burst - 1; remaining >= 0; remaining-- {
+		decision := take(t, store, testKey)
+		require.True(t, decision.Allowed)
+		require.Equal(t, remaining, decision.Remaining)
+		require.Equal(t, testLimit.Burst, decision.Limit)
+	}
+	rejected := take(t, store, testKey)
+
+	require.False(t, rejected.Allowed)
+	require.Equal(t, 0, rejected.Remaining)
+	require.Equal(t, 500*time.Millisecond, rejected.RetryAfter)
+	require.Equal(t, 1500*time.Millisecond, rejected.Reset)
+}
+
+func TestMemoryStore_Refills(t *testing.T) {
+	store, clock := newTestStore()
+	for range testLimit.Burst {
+		take(t, store, testKey)
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	refilled := take(t, store, testKey)
+	rejected := take(t, store, testKey)
+
+	require.True(t, refilled.Allowed)
+	require.False(t, rejected.Allowed)
+}
+
+func TestMemoryStore_RefillIsCappedAtBurst(t *testing.T) {
+	store, clock := newTestStore()
+	take(t, store, testKey)
+
+	clock.Advance(time.Hour)
+	decision := take(t, store, testKey)
+
+	require.Equal(t, testLimit.Burst-1, decision.Remaining)
+}
+
+func TestMemoryStore_KeysAreIndependent(t *testing.T) {
+	store, _ := newTestStore()
+	for range testLimit.Burst {
+		take(t, store, testKey)
+	}
+
+	decision := take(t, store, "ip:10.0.0.2")
+
+	require.True(t, decision.Allowed)
+}
+
+func TestMemoryStore_EvictsFullBuckets(t *testing.T) {
+	store, clock := newTestStore()
+	take(t, store, testKey)
+
+	store.evictFull()
+	require.Len(t, store.buckets, 1)
+	clock.Advance(time.Second)
+	store.evictFull()
+
+	require.Empty(t, store.buckets)
+}
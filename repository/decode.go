@@ -0,0 +1,377 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"educabot.com/bookshop/models"
+)
+
+const (
+	// PolicyReject fails the whole fetch when any book is invalid.
+	PolicyReject ValidationPolicy = "reject"
+	// PolicySkip drops invalid books and serves the rest.
+	PolicySkip ValidationPolicy = "skip"
+	// PolicyQuarantine drops invalid books like PolicySkip and keeps them,
+	// with their problems, for inspection through Quarantine.
+	PolicyQuarantine ValidationPolicy = "quarantine"
+
+	// ModeStrict requires every field to have exactly the JSON type of
+	// models.Book.
+	ModeStrict DecodingMode = "strict"
+	// ModeLenient also accepts numbers sent as strings, fractional numbers
+	// (rounded per RoundingPolicy) and null counts, recording a Coercion for
+	// each. IDs must still be whole numbers.
+	ModeLenient DecodingMode = "lenient"
+
+	RoundNearest RoundingPolicy = "nearest"
+	RoundDown    RoundingPolicy = "down"
+	RoundUp      RoundingPolicy = "up"
+	// RoundReject treats fractional numbers as malformed even in lenient mode.
+	RoundReject RoundingPolicy = "reject"
+
+	DefaultMaxBodyBytes int64 = 10 << 20
+
+	ReasonMalformed    = "malformed"
+	ReasonMissingField = "missing_field"
+	ReasonOutOfRange   = "out_of_range"
+	ReasonDuplicateID  = "duplicate_id"
+
+	CoercionNumericString = "numeric_string"
+	CoercionRounded       = "rounded"
+	CoercionNull          = "null"
+
+	fieldID        = "id"
+	fieldName      = "name"
+	fieldAuthor    = "author"
+	fieldUnitsSold = "units_sold"
+	fieldPrice     = "price"
+
+	maxBookPrice     = 1_000_000
+	maxBookUnitsSold = 1_000_000_000_000
+)
+
+type (
+	ValidationPolicy string
+	DecodingMode     string
+	RoundingPolicy   string
+
+	DecodeOptions struct {
+		MaxBodyBytes int64
+		Policy       ValidationPolicy
+		Mode         DecodingMode
+		Rounding     RoundingPolicy
+	}
+
+	// ValidationObserver is told about every problem found in an upstream
+	// book, once per problem, and about every value lenient decoding had to
+	// coerce, once per coercion.
+	ValidationObserver interface {
+		RecordInvalidBook(reason string)
+		RecordCoercion(kind string)
+	}
+
+	// InvalidBook is an upstream record that failed validation, identified by
+	// its position in the payload.
+	InvalidBook struct {
+		Index    int             `json:"index"`
+		Raw      json.RawMessage `json:"raw"`
+		Problems []Problem       `json:"problems"`
+	}
+
+	Problem struct {
+		Field  string `json:"field,omitempty"`
+		Reason string `json:"reason"`
+	}
+
+	// Coercion records a value lenient decoding accepted in a form strict
+	// decoding would have rejected.
+	Coercion struct {
+		Index int    `json:"index"`
+		Field string `json:"field"`
+		Kind  string `json:"kind"`
+		From  string `json:"from"`
+	}
+
+	bookRecord struct {
+		ID        json.RawMessage `json:"id"`
+		Name      json.RawMessage `json:"name"`
+		Author    json.RawMessage `json:"author"`
+		UnitsSold json.RawMessage `json:"units_sold"`
+		Price     json.RawMessage `json:"price"`
+	}
+
+	recordDecoder struct {
+		opts      DecodeOptions
+		index     int
+		problems  []Problem
+		coercions []Coercion
+	}
+
+	decodedCatalog struct {
+		books     []models.Book
+		invalid   []InvalidBook
+		coercions []Coercion
+	}
+)
+
+func DefaultDecodeOptions() DecodeOptions {
+	return DecodeOptions{MaxBodyBytes: DefaultMaxBodyBytes, Policy: PolicySkip, Mode: ModeStrict, Rounding: RoundNearest}
+}
+
+func (p Problem) String() string {
+	if p.Field == "" {
+		return p.Reason
+	}
+	return p.Field + ": " + p.Reason
+}
+
+// ID is the raw text of the book's id field, without quotes, or empty when
+// the record has none or isn't an object.
+func (b InvalidBook) ID() string {
+	var record bookRecord
+	if json.Unmarshal(b.Raw, &record) != nil {
+		return ""
+	}
+	return strings.Trim(string(record.ID), `"`)
+}
+
+// quarantined returns the invalid books to hold for inspection under policy.
+func (c decodedCatalog) quarantined(policy ValidationPolicy) []InvalidBook {
+	if policy != PolicyQuarantine {
+		return nil
+	}
+	return c.invalid
+}
+
+// enforcePolicy reports every invalid book to observer, when one is given,
+// logs a summary under source, and fails the catalog if policy rejects it.
+func enforcePolicy(ctx context.Context, catalog decodedCatalog, policy ValidationPolicy, observer ValidationObserver, source slog.Attr) ([]models.Book, error) {
+	if len(catalog.invalid) == 0 {
+		return catalog.books, nil
+	}
+	for _, invalid := range catalog.invalid {
+		slog.DebugContext(ctx, "upstream book failed validation",
+			"index", invalid.Index, "problems", invalid.Problems, "policy", policy)
+		if observer != nil {
+			for _, problem := range invalid.Problems {
+				observer.RecordInvalidBook(problem.Reason)
+			}
+		}
+	}
+	first := catalog.invalid[0]
+	slog.WarnContext(ctx, "upstream catalog contained invalid books",
+		source, "invalid", len(catalog.invalid), "valid", len(catalog.books),
+		"policy", policy, "first_index", first.Index, "first_problems", first.Problems)
+
+	if policy == PolicyReject {
+		return nil, fmt.Errorf("%w: %d invalid books, first at index %d (%s)",
+			ErrInvalidCatalog, len(catalog.invalid), first.Index, first.Problems[0])
+	}
+	return catalog.books, nil
+}
+
+// decodeCatalog reads the top-level array one book at a time, so a large
+// payload is never held as a whole, and sorts each book into valid or invalid.
+// Only malformed JSON or an oversized body fails the decode; what to do with
+// invalid books is left to the caller's policy.
+func decodeCatalog(body io.Reader, opts DecodeOptions) (decodedCatalog, error) {
+	decoder := json.NewDecoder(limitBody(body, opts.MaxBodyBytes))
+	token, err := decoder.Token()
+	if err != nil {
+		return decodedCatalog{}, wrapDecodeError(err)
+	}
+	if token == nil {
+		return decodedCatalog{}, nil
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return decodedCatalog{}, fmt.Errorf("%w: expected an array of books", ErrDecodingResponse)
+	}
+
+	var catalog decodedCatalog
+	seen := make(map[uint]bool)
+	for index := 0; decoder.More(); index++ {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return decodedCatalog{}, wrapDecodeError(err)
+		}
+		book, problems, coercions := validateBook(raw, index, seen, opts)
+		catalog.coercions = append(catalog.coercions, coercions...)
+		if len(problems) > 0 {
+			catalog.invalid = append(catalog.invalid, InvalidBook{Index: index, Raw: raw, Problems: problems})
+			continue
+		}
+		seen[book.ID] = true
+		catalog.books = append(catalog.books, book)
+	}
+	if _, err := decoder.Token(); err != nil {
+		return decodedCatalog{}, wrapDecodeError(err)
+	}
+	return catalog, nil
+}
+
+func validateBook(raw json.RawMessage, index int, seen map[uint]bool, opts DecodeOptions) (models.Book, []Problem, []Coercion) {
+	var record bookRecord
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return models.Book{}, []Problem{{Reason: ReasonMalformed}}, nil
+	}
+
+	d := &recordDecoder{opts: opts, index: index}
+	id := d.integer(fieldID, record.ID, false)
+	name := d.text(fieldName, record.Name)
+	author := d.text(fieldAuthor, record.Author)
+	unitsSold := d.integer(fieldUnitsSold, record.UnitsSold, true)
+	price := d.integer(fieldPrice, record.Price, true)
+
+	d.require(id != nil || d.failed(fieldID), fieldID, ReasonMissingField)
+	if id != nil && d.require(*id > 0, fieldID, ReasonOutOfRange) {
+		d.require(!seen[uint(*id)], fieldID, ReasonDuplicateID)
+	}
+	d.require((name != nil && strings.TrimSpace(*name) != "") || d.failed(fieldName), fieldName, ReasonMissingField)
+	d.require((author != nil && strings.TrimSpace(*author) != "") || d.failed(fieldAuthor), fieldAuthor, ReasonMissingField)
+	if d.require(unitsSold != nil || d.failed(fieldUnitsSold), fieldUnitsSold, ReasonMissingField) && unitsSold != nil {
+		d.require(*unitsSold >= 0 && *unitsSold <= maxBookUnitsSold, fieldUnitsSold, ReasonOutOfRange)
+	}
+	if d.require(price != nil || d.failed(fieldPrice), fieldPrice, ReasonMissingField) && price != nil {
+		d.require(*price >= 0 && *price <= maxBookPrice, fieldPrice, ReasonOutOfRange)
+	}
+	if len(d.problems) > 0 {
+		return models.Book{}, d.problems, d.coercions
+	}
+
+	return models.Book{
+		ID:        uint(*id),
+		Name:      *name,
+		Author:    *author,
+		UnitsSold: uint(*unitsSold),
+		Price:     uint(*price),
+	}, nil, d.coercions
+}
+
+func (d *recordDecoder) require(ok bool, field, reason string) bool {
+	if !ok {
+		d.problems = append(d.problems, Problem{Field: field, Reason: reason})
+	}
+	return ok
+}
+
+// failed reports whether field already has a problem, so a malformed value
+// isn't also reported as missing.
+func (d *recordDecoder) failed(field string) bool {
+	return slices.ContainsFunc(d.problems, func(p Problem) bool { return p.Field == field })
+}
+
+func (d *recordDecoder) coerce(field, kind string, raw json.RawMessage) {
+	d.coercions = append(d.coercions, Coercion{Index: d.index, Field: field, Kind: kind, From: string(raw)})
+}
+
+func (d *recordDecoder) text(field string, raw json.RawMessage) *string {
+	if isNull(raw) {
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		d.require(false, field, ReasonMalformed)
+		return nil
+	}
+	return &value
+}
+
+// integer decodes a whole number. Counts, unlike IDs, may be null or
+// fractional in lenient mode: null reads as zero and fractions are rounded.
+func (d *recordDecoder) integer(field string, raw json.RawMessage, count bool) *int64 {
+	if len(raw) == 0 || (isNull(raw) && (d.opts.Mode != ModeLenient || !count)) {
+		return nil
+	}
+	if d.opts.Mode != ModeLenient {
+		var value int64
+		if err := json.Unmarshal(raw, &value); err != nil {
+			d.require(false, field, ReasonMalformed)
+			return nil
+		}
+		return &value
+	}
+
+	if isNull(raw) {
+		d.coerce(field, CoercionNull, raw)
+		return new(int64)
+	}
+	text := string(raw)
+	if strings.HasPrefix(text, `"`) {
+		if err := json.Unmarshal(raw, &text); err != nil {
+			d.require(false, field, ReasonMalformed)
+			return nil
+		}
+		text = strings.TrimSpace(text)
+	}
+	if value, err := strconv.ParseInt(text, 10, 64); err == nil {
+		if text != string(raw) {
+			d.coerce(field, CoercionNumericString, raw)
+		}
+		return &value
+	}
+
+	number, err := strconv.ParseFloat(text, 64)
+	if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+		d.require(false, field, ReasonMalformed)
+		return nil
+	}
+	if !d.require(math.Abs(number) < math.MaxInt64, field, ReasonOutOfRange) {
+		return nil
+	}
+	if text != string(raw) {
+		d.coerce(field, CoercionNumericString, raw)
+	}
+	if number == math.Trunc(number) {
+		value := int64(number)
+		return &value
+	}
+	rounded, ok := roundNumber(number, d.opts.Rounding)
+	if !d.require(ok && count, field, ReasonMalformed) {
+		return nil
+	}
+	d.coerce(field, CoercionRounded, raw)
+	value := int64(rounded)
+	return &value
+}
+
+func roundNumber(number float64, rounding RoundingPolicy) (float64, bool) {
+	switch rounding {
+	case RoundNearest:
+		return math.Round(number), true
+	case RoundDown:
+		return math.Floor(number), true
+	case RoundUp:
+		return math.Ceil(number), true
+	default:
+		return 0, false
+	}
+}
+
+func isNull(raw json.RawMessage) bool {
+	return len(raw) == 0 || string(raw) == "null"
+}
+
+func limitBody(body io.Reader, maxBodyBytes int64) io.Reader {
+	if maxBodyBytes <= 0 {
+		return body
+	}
+	return http.MaxBytesReader(nil, io.NopCloser(body), maxBodyBytes)
+}
+
+func wrapDecodeError(err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return fmt.Errorf("%w: limit is %d bytes", ErrResponseTooLarge, tooLarge.Limit)
+	}
+	return fmt.Errorf("%w: %w", ErrDecodingResponse, err)
+}
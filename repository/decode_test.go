@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"educabot.com/bookshop/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeCatalog_ValidatesEachBook(t *testing.T) {
+	tests := []struct {
+		name     string
+		book     string
+		problems []Problem
+	}{
+		{
+			name:     "missing fields",
+			book:     `{"id":2}`,
+			problems: []Problem{{fieldName, ReasonMissingField}, {fieldAuthor, ReasonMissingField}, {fieldUnitsSold, ReasonMissingField}, {fieldPrice, ReasonMissingField}},
+		},
+		{
+			name:     "blank name",
+			book:     `{"id":2,"name":"  ","author":"Frank Herbert","units_sold":1,"price":1}`,
+			problems: []Problem{{fieldName, ReasonMissingField}},
+		},
+		{
+			name:     "negative numbers",
+			book:     `{"id":2,"name":"Dune","author":"Frank Herbert","units_sold":-1,"price":-5}`,
+			problems: []Problem{{fieldUnitsSold, ReasonOutOfRange}, {fieldPrice, ReasonOutOfRange}},
+		},
+		{
+			name:     "zero id",
+			book:     `{"id":0,"name":"Dune","author":"Frank Herbert","units_sold":1,"price":1}`,
+			problems: []Problem{{fieldID, ReasonOutOfRange}},
+		},
+		{
+			name:     "price out of range",
+			book:     `{"id":2,"name":"Dune","author":"Frank Herbert","units_sold":1,"price":1000001}`,
+			problems: []Problem{{fieldPrice, ReasonOutOfRange}},
+		},
+		{
+			name:     "duplicate id",
+			book:     `{"id":1,"name":"Dune","author":"Frank Herbert","units_sold":1,"price":1}`,
+			problems: []Problem{{fieldID, ReasonDuplicateID}},
+		},
+		{
+			name:     "wrong type",
+			book:     `{"id":"2","name":"Dune","author":"Frank Herbert","units_sold":1,"price":1}`,
+			problems: []Problem{{fieldID, ReasonMalformed}},
+		},
+		{
+			name:     "not an object",
+			book:     `"Dune"`,
+			problems: []Problem{{Reason: ReasonMalformed}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `[` + strings.TrimSuffix(strings.TrimPrefix(validBooksJSON, "["), "]") + `,` + tt.book + `]`
+
+			catalog, err := decodeCatalog(strings.NewReader(body), DefaultDecodeOptions())
+
+			require.NoError(t, err)
+			require.Len(t, catalog.books, 1)
+			require.Len(t, catalog.invalid, 1)
+			require.Equal(t, 1, catalog.invalid[0].Index)
+			require.JSONEq(t, tt.book, string(catalog.invalid[0].Raw))
+			require.Equal(t, tt.problems, catalog.invalid[0].Problems)
+		})
+	}
+}
+
+func TestDecodeCatalog_Null(t *testing.T) {
+	catalog, err := decodeCatalog(strings.NewReader("null"), DefaultDecodeOptions())
+
+	require.NoError(t, err)
+	require.Empty(t, catalog.books)
+}
+
+func TestDecodeCatalog_NotAnArray(t *testing.T) {
+	_, err := decodeCatalog(strings.NewReader(`{"books":[]}`), DefaultDecodeOptions())
+
+	require.ErrorIs(t, err, ErrDecodingResponse)
+}
+
+func TestDecodeCatalog_TruncatedArray(t *testing.T) {
+	_, err := decodeCatalog(strings.NewReader(strings.TrimSuffix(validBooksJSON, "]")), DefaultDecodeOptions())
+
+	require.ErrorIs(t, err, ErrDecodingResponse)
+}
+
+func TestDecodeCatalog_MaxBodyBytes(t *testing.T) {
+	exactOpts := DefaultDecodeOptions()
+	exactOpts.MaxBodyBytes = int64(len(validBooksJSON))
+	smallOpts := DefaultDecodeOptions()
+	smallOpts.MaxBodyBytes = int64(len(validBooksJSON) - 1)
+
+	exact, exactErr := decodeCatalog(strings.NewReader(validBooksJSON), exactOpts)
+	_, tooLargeErr := decodeCatalog(strings.NewReader(validBooksJSON), smallOpts)
+
+	require.NoError(t, exactErr)
+	require.Len(t, exact.books, 1)
+	require.ErrorIs(t, tooLargeErr, ErrResponseTooLarge)
+	require.NotErrorIs(t, tooLargeErr, ErrDecodingResponse)
+}
+
+func lenientOptions(rounding RoundingPolicy) DecodeOptions {
+	opts := DefaultDecodeOptions()
+	opts.Mode = ModeLenient
+	opts.Rounding = rounding
+	return opts
+}
+
+func TestDecodeCatalog_LenientCoercions(t *testing.T) {
+	tests := []struct {
+		name      string
+		book      string
+		rounding  RoundingPolicy
+		want      models.Book
+		coercions []string
+	}{
+		{
+			name:      "numeric strings",
+			book:      `{"id":"12","name":"Dune","author":"Frank Herbert","units_sold":" 300 ","price":"15"}`,
+			rounding:  RoundNearest,
+			want:      models.Book{ID: 12, Name: "Dune", Author: "Frank Herbert", UnitsSold: 300, Price: 15},
+			coercions: []string{CoercionNumericString, CoercionNumericString, CoercionNumericString},
+		},
+		{
+			name:      "rounded to nearest",
+			book:      `{"id":12,"name":"Dune","author":"Frank Herbert","units_sold":300,"price":19.5}`,
+			rounding:  RoundNearest,
+			want:      models.Book{ID: 12, Name: "Dune", Author: "Frank Herbert", UnitsSold: 300, Price: 20},
+			coercions: []string{CoercionRounded},
+		},
+		{
+			name:      "rounded down from string",
+			book:      `{"id":12,"name":"Dune","author":"Frank Herbert","units_sold":300,"price":"19.99"}`,
+			rounding:  RoundDown,
+			want:      models.Book{ID: 12, Name: "Dune", Author: "Frank Herbert", UnitsSold: 300, Price: 19},
+			coercions: []string{CoercionNumericString, CoercionRounded},
+		},
+		{
+			name:      "rounded up",
+			book:      `{"id":12,"name":"Dune","author":"Frank Herbert","units_sold":300,"price":19.01}`,
+			rounding:  RoundUp,
+			want:      models.Book{ID: 12, Name: "Dune", Author: "Frank Herbert", UnitsSold: 300, Price: 20},
+			coercions: []string{CoercionRounded},
+		},
+		{
+			name:     "whole float",
+			book:     `{"id":12.0,"name":"Dune","author":"Frank Herbert","units_sold":3e2,"price":15}`,
+			rounding: RoundReject,
+			want:     models.Book{ID: 12, Name: "Dune", Author: "Frank Herbert", UnitsSold: 300, Price: 15},
+		},
+		{
+			name:      "null count",
+			book:      `{"id":12,"name":"Dune","author":"Frank Herbert","units_sold":null,"price":15}`,
+			rounding:  RoundNearest,
+			want:      models.Book{ID: 12, Name: "Dune", Author: "Frank Herbert", UnitsSold: 0, Price: 15},
+			coercions: []string{CoercionNull},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			catalog, err := decodeCatalog(strings.NewReader("["+tt.book+"]"), lenientOptions(tt.rounding))
+
+			require.NoError(t, err)
+			require.Empty(t, catalog.invalid)
+			require.Equal(t, []models.Book{tt.want}, catalog.books)
+			kinds := make([]string, 0, len(catalog.coercions))
+			for _, coercion := range catalog.coercions {
+				kinds = append(kinds, coercion.Kind)
+			}
+			require.ElementsMatch(t, tt.coercions, kinds)
+		})
+	}
+}
+
+func TestDecodeCatalog_LenientStillRejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		book     string
+		rounding RoundingPolicy
+		problems []Problem
+	}{
+		{
+			name:     "fractional id",
+			book:     `{"id":1.5,"name":"Dune","author":"Frank Herbert","units_sold":1,"price":1}`,
+			rounding: RoundNearest,
+			problems: []Problem{{fieldID, ReasonMalformed}},
+		},
+		{
+			name:     "null id",
+			book:     `{"id":null,"name":"Dune","author":"Frank Herbert","units_sold":1,"price":1}`,
+			rounding: RoundNearest,
+			problems: []Problem{{fieldID, ReasonMissingField}},
+		},
+		{
+			name:     "non-numeric string",
+			book:     `{"id":2,"name":"Dune","author":"Frank Herbert","units_sold":"many","price":1}`,
+			rounding: RoundNearest,
+			problems: []Problem{{fieldUnitsSold, ReasonMalformed}},
+		},
+		{
+			name:     "fraction with reject rounding",
+			book:     `{"id":2,"name":"Dune","author":"Frank Herbert","units_sold":1,"price":19.99}`,
+			rounding: RoundReject,
+			problems: []Problem{{fieldPrice, ReasonMalformed}},
+		},
+		{
+			name:     "negative after rounding",
+			book:     `{"id":2,"name":"Dune","author":"Frank Herbert","units_sold":1,"price":-0.6}`,
+			rounding: RoundNearest,
+			problems: []Problem{{fieldPrice, ReasonOutOfRange}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			catalog, err := decodeCatalog(strings.NewReader("["+tt.book+"]"), lenientOptions(tt.rounding))
+
+			require.NoError(t, err)
+			require.Empty(t, catalog.books)
+			require.Equal(t, tt.problems, catalog.invalid[0].Problems)
+		})
+	}
+}
+
+func TestDecodeCatalog_StrictRejectsCoercibleValues(t *testing.T) {
+	body := `[{"id":"12","name":"Dune","author":"Frank Herbert","units_sold":null,"price":19.99}]`
+
+	catalog, err := decodeCatalog(strings.NewReader(body), DefaultDecodeOptions())
+
+	require.NoError(t, err)
+	require.Empty(t, catalog.books)
+	require.Empty(t, catalog.coercions)
+	require.Equal(t, []Problem{
+		{fieldID, ReasonMalformed},
+		{fieldPrice, ReasonMalformed},
+		{fieldUnitsSold, ReasonMissingField},
+	}, catalog.invalid[0].Problems)
+}
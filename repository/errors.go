@@ -1,10 +1,56 @@
 package repository
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+const (
+	ErrorCategoryTimeout        = "timeout"
+	ErrorCategoryCanceled       = "canceled"
+	ErrorCategoryRequest        = "request"
+	ErrorCategoryStatus         = "status"
+	ErrorCategoryTooLarge       = "too_large"
+	ErrorCategoryDecode         = "decode"
+	ErrorCategoryInvalidCatalog = "invalid_catalog"
+	ErrorCategoryFile           = "file"
+	ErrorCategoryOther          = "other"
+)
 
 var (
 	ErrCreatingRequest    = errors.New("creating request")
 	ErrExecutingRequest   = errors.New("executing request")
 	ErrUnexpectedStatus   = errors.New("unexpected status code")
 	ErrDecodingResponse   = errors.New("decoding response")
+	ErrResponseTooLarge   = errors.New("response body too large")
+	ErrInvalidCatalog     = errors.New("catalog failed validation")
+	ErrReadingCatalogFile = errors.New("reading catalog file")
 )
+
+// ErrorCategory names the kind of failure err is, for reports that are shown
+// outside the process and so must not carry the error's text, which can hold
+// upstream URLs or response bodies.
+func ErrorCategory(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorCategoryTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorCategoryCanceled
+	case errors.Is(err, ErrCreatingRequest), errors.Is(err, ErrExecutingRequest):
+		return ErrorCategoryRequest
+	case errors.Is(err, ErrUnexpectedStatus):
+		return ErrorCategoryStatus
+	case errors.Is(err, ErrResponseTooLarge):
+		return ErrorCategoryTooLarge
+	case errors.Is(err, ErrInvalidCatalog):
+		return ErrorCategoryInvalidCatalog
+	case errors.Is(err, ErrDecodingResponse):
+		return ErrorCategoryDecode
+	case errors.Is(err, ErrReadingCatalogFile):
+		return ErrorCategoryFile
+	default:
+		return ErrorCategoryOther
+	}
+}
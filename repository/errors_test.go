@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestErrorCategory(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{fmt.Errorf("%w: %w", ErrExecutingRequest, timeoutError{}), ErrorCategoryTimeout},
+		{fmt.Errorf("%w: %w", ErrExecutingRequest, context.DeadlineExceeded), ErrorCategoryTimeout},
+		{fmt.Errorf("%w: %w", ErrExecutingRequest, context.Canceled), ErrorCategoryCanceled},
+		{fmt.Errorf("%w: connection refused", ErrExecutingRequest), ErrorCategoryRequest},
+		{fmt.Errorf("%w: 503", ErrUnexpectedStatus), ErrorCategoryStatus},
+		{fmt.Errorf("%w: limit is 10 bytes", ErrResponseTooLarge), ErrorCategoryTooLarge},
+		{fmt.Errorf("%w: %w: EOF", ErrInvalidCatalog, ErrDecodingResponse), ErrorCategoryInvalidCatalog},
+		{fmt.Errorf("%w: EOF", ErrDecodingResponse), ErrorCategoryDecode},
+		{fmt.Errorf("%w: no such file", ErrReadingCatalogFile), ErrorCategoryFile},
+		{errors.New("boom"), ErrorCategoryOther},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, ErrorCategory(tt.err), tt.err.Error())
+	}
+}
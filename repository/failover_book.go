@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"educabot.com/bookshop/models"
+)
+
+const DefaultFailoverCooldown = 30 * time.Second
+
+// FailoverBookRepository asks its providers in priority order and serves the
+// first catalog it gets. A provider that fails is skipped until its cooldown
+// passes; after that it is asked first again, so a recovered primary takes
+// over on the next call without intervention.
+type FailoverBookRepository struct {
+	providers []Provider
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu      sync.Mutex
+	health  []ProviderStatus
+	serving int
+	status  FetchStatus
+}
+
+func NewFailoverBookRepository(providers []Provider, cooldown time.Duration) *FailoverBookRepository {
+	sorted := slices.Clone(providers)
+	slices.SortStableFunc(sorted, func(a, b Provider) int { return a.Priority - b.Priority })
+	health := make([]ProviderStatus, len(sorted))
+	for i, provider := range sorted {
+		health[i] = ProviderStatus{Name: provider.Name, Priority: provider.Priority}
+	}
+	return &FailoverBookRepository{providers: sorted, cooldown: cooldown, now: time.Now, health: health, serving: -1}
+}
+
+// GetBooks tries the providers that are not cooling down first and, only if
+// all of those fail, the ones that are, so a chain whose providers all failed
+// recently still gets a chance to recover on every call.
+func (r *FailoverBookRepository) GetBooks(ctx context.Context) ([]models.Book, error) {
+	var coolingDown []int
+	var errs []error
+	now := r.now()
+	for i := range r.providers {
+		if r.coolingDown(i, now) {
+			coolingDown = append(coolingDown, i)
+			continue
+		}
+		books, err := r.try(ctx, i)
+		if err == nil {
+			return books, nil
+		}
+		errs = append(errs, err)
+	}
+	for _, i := range coolingDown {
+		books, err := r.try(ctx, i)
+		if err == nil {
+			return books, nil
+		}
+		errs = append(errs, err)
+	}
+
+	err := fmt.Errorf("%w: %w", ErrAllProvidersFailed, errors.Join(errs...))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status.LastAttempt = r.now()
+	r.status.LastError = err
+	return nil, err
+}
+
+// ServingProvider names the provider that served the last successful call.
+func (r *FailoverBookRepository) ServingProvider() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.serving < 0 {
+		return ""
+	}
+	return r.providers[r.serving].Name
+}
+
+// Report returns each provider's health, in priority order.
+func (r *FailoverBookRepository) Report() []ProviderStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.health)
+}
+
+// FetchStatus counts a call as successful when any provider served it, so
+// LastError is only set while every provider is failing.
+func (r *FailoverBookRepository) FetchStatus() FetchStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status
+}
+
+func (r *FailoverBookRepository) coolingDown(i int, now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return now.Before(r.health[i].RetryAt)
+}
+
+func (r *FailoverBookRepository) try(ctx context.Context, i int) ([]models.Book, error) {
+	provider := r.providers[i]
+	start := r.now()
+	books, err := provider.Repository.GetBooks(ctx)
+	finished := r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	health := &r.health[i]
+	health.Duration = finished.Sub(start)
+	if err != nil {
+		health.Error = ErrorCategory(err)
+		health.RetryAt = finished.Add(r.cooldown)
+		slog.WarnContext(ctx, "book provider failed",
+			"provider", provider.Name, "retry_at", health.RetryAt, "error", err)
+		return nil, fmt.Errorf("%s: %w", provider.Name, err)
+	}
+
+	health.Books = len(books)
+	health.Error = ""
+	health.RetryAt = time.Time{}
+	if reporter, ok := provider.Repository.(FreshnessReporter); ok {
+		health.ModifiedAt = reporter.CatalogModifiedAt()
+	}
+	if r.serving != i {
+		previous := ""
+		if r.serving >= 0 {
+			r.health[r.serving].Serving = false
+			previous = r.providers[r.serving].Name
+		}
+		slog.InfoContext(ctx, "book provider changed", "from", previous, "to", provider.Name)
+		r.serving = i
+		health.Serving = true
+	}
+	r.status.LastAttempt = finished
+	r.status.LastSuccess = finished
+	r.status.LastError = nil
+	if reporter, ok := provider.Repository.(interface{ FetchStatus() FetchStatus }); ok {
+		r.status.InvalidBooks = reporter.FetchStatus().InvalidBooks
+	}
+	RecordSource(ctx, provider.Name)
+	return books, nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/models"
+	"educabot.com/bookshop/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+const testCooldown = time.Minute
+
+type failoverClock struct {
+	now time.Time
+}
+
+func (c *failoverClock) Now() time.Time { return c.now }
+
+type countingRepository struct {
+	*mocks.MockBookRepository
+	calls int
+}
+
+func (r *countingRepository) GetBooks(ctx context.Context) ([]models.Book, error) {
+	r.calls++
+	return r.MockBookRepository.GetBooks(ctx)
+}
+
+func newTestFailover(primary, backup *countingRepository) (*FailoverBookRepository, *failoverClock) {
+	clock := &failoverClock{now: time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)}
+	repo := NewFailoverBookRepository([]Provider{
+		{Name: "backup", Priority: 2, Repository: backup},
+		{Name: "primary", Priority: 1, Repository: primary},
+	}, testCooldown)
+	repo.now = clock.Now
+	return repo, clock
+}
+
+func counting(repo *mocks.MockBookRepository) *countingRepository {
+	return &countingRepository{MockBookRepository: repo}
+}
+
+func TestFailoverBookRepository_ServesPrimaryWhenHealthy(t *testing.T) {
+	primary := counting(providerBooks(duneCheap))
+	backup := counting(providerBooks(hobbit))
+	repo, _ := newTestFailover(primary, backup)
+	ctx, source := WithSource(context.Background())
+
+	books, err := repo.GetBooks(ctx)
+
+	require.NoError(t, err)
+	require.Equal(t, []models.Book{duneCheap}, books)
+	require.Equal(t, "primary", source.Provider())
+	require.Equal(t, "primary", repo.ServingProvider())
+	require.Zero(t, backup.calls)
+}
+
+func TestFailoverBookRepository_FallsBackAndRepromotes(t *testing.T) {
+	primary := counting(mocks.NewMockBookRepository().WithError(errProviderDown))
+	backup := counting(providerBooks(hobbit))
+	repo, clock := newTestFailover(primary, backup)
+
+	failedOver, err := repo.GetBooks(context.Background())
+	require.NoError(t, err)
+	servedBy := repo.ServingProvider()
+	report := repo.Report()
+	clock.now = clock.now.Add(testCooldown / 2)
+	_, err = repo.GetBooks(context.Background())
+	require.NoError(t, err)
+	callsDuringCooldown := primary.calls
+	primary.WithError(nil).WithBooks([]models.Book{duneCheap})
+	clock.now = clock.now.Add(testCooldown)
+	recovered, err := repo.GetBooks(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, []models.Book{hobbit}, failedOver)
+	require.Equal(t, "backup", servedBy)
+	require.Equal(t, ErrorCategoryOther, report[0].Error)
+	require.False(t, report[0].RetryAt.IsZero())
+	require.True(t, report[1].Serving)
+	require.Equal(t, 1, callsDuringCooldown)
+	require.Equal(t, []models.Book{duneCheap}, recovered)
+	require.Equal(t, "primary", repo.ServingProvider())
+	require.Empty(t, repo.Report()[0].Error)
+	require.False(t, repo.Report()[1].Serving)
+}
+
+func TestFailoverBookRepository_RetriesCoolingDownProvidersLast(t *testing.T) {
+	primary := counting(mocks.NewMockBookRepository().WithError(errProviderDown))
+	backup := counting(mocks.NewMockBookRepository().WithError(errProviderDown))
+	repo, _ := newTestFailover(primary, backup)
+
+	_, firstErr := repo.GetBooks(context.Background())
+	primary.WithError(nil).WithBooks([]models.Book{duneCheap})
+	books, err := repo.GetBooks(context.Background())
+
+	require.ErrorIs(t, firstErr, ErrAllProvidersFailed)
+	require.ErrorIs(t, firstErr, errProviderDown)
+	require.NoError(t, err)
+	require.Equal(t, []models.Book{duneCheap}, books)
+	require.Equal(t, 2, primary.calls)
+	require.Equal(t, 1, backup.calls)
+}
+
+func TestFailoverBookRepository_FetchStatus(t *testing.T) {
+	primary := counting(mocks.NewMockBookRepository().WithError(errProviderDown))
+	backup := counting(mocks.NewMockBookRepository().WithError(errProviderDown))
+	repo, _ := newTestFailover(primary, backup)
+
+	_, err := repo.GetBooks(context.Background())
+	failed := repo.FetchStatus()
+	backup.WithError(nil).WithBooks([]models.Book{hobbit})
+	_, err = repo.GetBooks(context.Background())
+	succeeded := repo.FetchStatus()
+
+	require.NoError(t, err)
+	require.True(t, failed.LastSuccess.IsZero())
+	require.ErrorIs(t, failed.LastError, ErrAllProvidersFailed)
+	require.False(t, succeeded.LastSuccess.IsZero())
+	require.NoError(t, succeeded.LastError)
+}
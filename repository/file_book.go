@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"educabot.com/bookshop/models"
+)
+
+// FileBookRepository serves a catalog stored on disk in the same JSON format
+// the upstream sends. The file is read on every call, so replacing it takes
+// effect without a restart.
+type FileBookRepository struct {
+	path      string
+	decode    DecodeOptions
+	validator ValidationObserver
+
+	mu         sync.Mutex
+	modifiedAt time.Time
+	quarantine []InvalidBook
+}
+
+func NewFileBookRepository(path string) *FileBookRepository {
+	return &FileBookRepository{path: path, decode: DefaultDecodeOptions()}
+}
+
+func (r *FileBookRepository) WithDecodeOptions(opts DecodeOptions) *FileBookRepository {
+	r.decode = opts
+	return r
+}
+
+func (r *FileBookRepository) WithValidationObserver(observer ValidationObserver) *FileBookRepository {
+	r.validator = observer
+	return r
+}
+
+// GetBooks applies the validation policy the same way HTTPBookRepository
+// does, so a file fallback is held to the upstream's standard.
+func (r *FileBookRepository) GetBooks(ctx context.Context) ([]models.Book, error) {
+	file, err := os.Open(r.path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReadingCatalogFile, err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReadingCatalogFile, err)
+	}
+
+	catalog, err := decodeCatalog(file, r.decode)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	r.quarantine = catalog.quarantined(r.decode.Policy)
+	r.mu.Unlock()
+	books, err := enforcePolicy(ctx, catalog, r.decode.Policy, r.validator, slog.String("path", r.path))
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	r.modifiedAt = info.ModTime()
+	r.mu.Unlock()
+	return books, nil
+}
+
+// Quarantine returns the invalid books held back from the last read under
+// PolicyQuarantine.
+func (r *FileBookRepository) Quarantine() []InvalidBook {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.quarantine)
+}
+
+// CatalogModifiedAt is the file's modification time as of the last read.
+func (r *FileBookRepository) CatalogModifiedAt() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.modifiedAt
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeCatalogFile(t *testing.T, body string) string {
+	path := filepath.Join(t.TempDir(), "catalog.json")
+	require.NoError(t, os.WriteFile(path, []byte(body), 0o600))
+	return path
+}
+
+func TestFileBookRepository_ReadsCatalog(t *testing.T) {
+	repo := NewFileBookRepository(writeCatalogFile(t, mixedBooksJSON))
+
+	books, err := repo.GetBooks(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, books, 1)
+	require.Equal(t, expectedBookName, books[0].Name)
+	require.False(t, repo.CatalogModifiedAt().IsZero())
+}
+
+func TestFileBookRepository_RejectPolicy(t *testing.T) {
+	opts := DefaultDecodeOptions()
+	opts.Policy = PolicyReject
+	repo := NewFileBookRepository(writeCatalogFile(t, mixedBooksJSON)).WithDecodeOptions(opts)
+
+	_, err := repo.GetBooks(context.Background())
+
+	require.ErrorIs(t, err, ErrInvalidCatalog)
+}
+
+func TestFileBookRepository_MissingFile(t *testing.T) {
+	repo := NewFileBookRepository(filepath.Join(t.TempDir(), "missing.json"))
+
+	_, err := repo.GetBooks(context.Background())
+
+	require.ErrorIs(t, err, ErrReadingCatalogFile)
+}
+
+func TestFileBookRepository_QuarantinePolicy(t *testing.T) {
+	opts := DefaultDecodeOptions()
+	opts.Policy = PolicyQuarantine
+	observer := &reasonObserver{}
+	repo := NewFileBookRepository(writeCatalogFile(t, mixedBooksJSON)).WithDecodeOptions(opts).WithValidationObserver(observer)
+
+	books, err := repo.GetBooks(context.Background())
+	quarantine := repo.Quarantine()
+
+	require.NoError(t, err)
+	require.Len(t, books, 1)
+	require.Len(t, quarantine, 1)
+	require.Equal(t, "2", quarantine[0].ID())
+	require.Equal(t, []string{ReasonMissingField, ReasonOutOfRange}, observer.reasons)
+}
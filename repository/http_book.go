@@ -1,29 +1,179 @@
 package repository
 
 import (
+	"compress/gzip"
 	"context"
-	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"educabot.com/bookshop/models"
 )
 
-var booksAPIURL = "https://6781684b85151f714b0aa5db.mockapi.io/api/v1/books"
+const (
+	headerETag            = "ETag"
+	headerLastModified    = "Last-Modified"
+	headerIfNoneMatch     = "If-None-Match"
+	headerIfModifiedSince = "If-Modified-Since"
+	headerAcceptEncoding  = "Accept-Encoding"
+	headerContentEncoding = "Content-Encoding"
 
-type HTTPBookRepository struct {
-	client *http.Client
+	encodingGzip = "gzip"
+
+	unparsableURL = "unparsable URL"
+)
+
+type (
+	HTTPBookRepository struct {
+		client *http.Client
+		url    string
+		// logURL is url with any password masked, for log records.
+		logURL string
+
+		observer  CacheObserver
+		validator ValidationObserver
+		decode    DecodeOptions
+
+		mu         sync.Mutex
+		status     FetchStatus
+		cached     cachedCatalog
+		quarantine []InvalidBook
+		modifiedAt time.Time
+	}
+
+	// FetchStatus describes the latest fetches. InvalidBooks counts the books
+	// that failed validation in the last catalog actually downloaded.
+	FetchStatus struct {
+		LastAttempt  time.Time
+		LastSuccess  time.Time
+		LastError    error
+		InvalidBooks int
+	}
+
+	// CacheObserver is told whether each successful fetch reused the
+	// previously decoded catalog after a 304 or downloaded a fresh one.
+	CacheObserver interface {
+		RecordCacheHit()
+		RecordCacheMiss()
+	}
+
+	cachedCatalog struct {
+		etag         string
+		lastModified string
+		books        []models.Book
+	}
+)
+
+func NewHTTPBookRepository(client *http.Client, url string) *HTTPBookRepository {
+	return &HTTPBookRepository{client: client, url: url, logURL: redactURL(url), decode: DefaultDecodeOptions()}
 }
 
-func NewHTTPBookRepository(client *http.Client) *HTTPBookRepository {
-	return &HTTPBookRepository{client: client}
+// redactURL masks the password of rawURL. A URL that doesn't parse is left
+// out altogether rather than logged with whatever credentials it holds.
+func redactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return unparsableURL
+	}
+	return parsed.Redacted()
+}
+
+func (r *HTTPBookRepository) WithCacheObserver(observer CacheObserver) *HTTPBookRepository {
+	r.observer = observer
+	return r
+}
+
+func (r *HTTPBookRepository) WithValidationObserver(observer ValidationObserver) *HTTPBookRepository {
+	r.validator = observer
+	return r
+}
+
+func (r *HTTPBookRepository) WithDecodeOptions(opts DecodeOptions) *HTTPBookRepository {
+	r.decode = opts
+	return r
 }
 
 func (r *HTTPBookRepository) GetBooks(ctx context.Context) ([]models.Book, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, booksAPIURL, nil)
+	start := time.Now()
+	books, err := r.fetchBooks(ctx)
+	r.recordFetch(err)
+	if err != nil {
+		slog.WarnContext(ctx, "fetching books from upstream failed",
+			"url", r.logURL, "duration", time.Since(start), "error", err)
+		return nil, err
+	}
+	slog.DebugContext(ctx, "fetched books from upstream",
+		"url", r.logURL, "duration", time.Since(start), "books", len(books))
+	return books, nil
+}
+
+func (r *HTTPBookRepository) FetchStatus() FetchStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status
+}
+
+// Quarantine returns the invalid books held back from the last downloaded
+// catalog under PolicyQuarantine.
+func (r *HTTPBookRepository) Quarantine() []InvalidBook {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.quarantine)
+}
+
+// CatalogModifiedAt is the upstream's Last-Modified for the catalog last
+// downloaded, or the time it was downloaded when the upstream sent none. A 304
+// leaves it unchanged.
+func (r *HTTPBookRepository) CatalogModifiedAt() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.modifiedAt
+}
+
+func (r *HTTPBookRepository) recordModified(header http.Header) {
+	modifiedAt, err := http.ParseTime(header.Get(headerLastModified))
+	if err != nil {
+		modifiedAt = time.Now()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.modifiedAt = modifiedAt
+}
+
+func (r *HTTPBookRepository) recordFetch(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status.LastAttempt = time.Now()
+	r.status.LastError = err
+	if err == nil {
+		r.status.LastSuccess = r.status.LastAttempt
+	}
+}
+
+func (r *HTTPBookRepository) fetchBooks(ctx context.Context) ([]models.Book, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrCreatingRequest, err)
 	}
+	// Asking for gzip explicitly turns off the transport's transparent
+	// decompression, so wrappers around the transport see the same encoding
+	// the upstream sent and the body is decoded in decodeBooks instead.
+	req.Header.Set(headerAcceptEncoding, encodingGzip)
+	cached := r.cachedCatalog()
+	if cached.books != nil {
+		if cached.etag != "" {
+			req.Header.Set(headerIfNoneMatch, cached.etag)
+		}
+		if cached.lastModified != "" {
+			req.Header.Set(headerIfModifiedSince, cached.lastModified)
+		}
+	}
 
 	resp, err := r.client.Do(req)
 	if err != nil {
@@ -31,14 +181,103 @@ func (r *HTTPBookRepository) GetBooks(ctx context.Context) ([]models.Book, error
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && cached.books != nil {
+		r.recordCacheLookup(true)
+		return slices.Clone(cached.books), nil
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("%w: %d", ErrUnexpectedStatus, resp.StatusCode)
 	}
 
-	var books []models.Book
-	if err := json.NewDecoder(resp.Body).Decode(&books); err != nil {
-		return nil, fmt.Errorf("%w: %w", ErrDecodingResponse, err)
+	catalog, err := decodeBody(resp, r.decode)
+	if err != nil {
+		return nil, err
+	}
+	books, err := r.applyPolicy(ctx, catalog)
+	if err != nil {
+		return nil, err
 	}
+	r.recordCacheLookup(false)
+	r.storeCatalog(resp.Header, books)
+	r.recordModified(resp.Header)
 
 	return books, nil
 }
+
+// decodeBody applies the size limit after decompression, so a small gzip
+// body can't expand past it.
+func decodeBody(resp *http.Response, opts DecodeOptions) (decodedCatalog, error) {
+	var body io.Reader = resp.Body
+	if strings.EqualFold(resp.Header.Get(headerContentEncoding), encodingGzip) {
+		reader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return decodedCatalog{}, fmt.Errorf("%w: %w", ErrDecodingResponse, err)
+		}
+		defer reader.Close()
+		body = reader
+	}
+	return decodeCatalog(body, opts)
+}
+
+func (r *HTTPBookRepository) applyPolicy(ctx context.Context, catalog decodedCatalog) ([]models.Book, error) {
+	r.mu.Lock()
+	r.status.InvalidBooks = len(catalog.invalid)
+	r.quarantine = catalog.quarantined(r.decode.Policy)
+	r.mu.Unlock()
+
+	r.reportCoercions(ctx, catalog.coercions)
+	return enforcePolicy(ctx, catalog, r.decode.Policy, r.validator, slog.String("url", r.logURL))
+}
+
+func (r *HTTPBookRepository) reportCoercions(ctx context.Context, coercions []Coercion) {
+	if len(coercions) == 0 {
+		return
+	}
+	for _, coercion := range coercions {
+		slog.DebugContext(ctx, "coerced upstream book value",
+			"index", coercion.Index, "field", coercion.Field, "kind", coercion.Kind, "from", coercion.From)
+		if r.validator != nil {
+			r.validator.RecordCoercion(coercion.Kind)
+		}
+	}
+	first := coercions[0]
+	slog.WarnContext(ctx, "upstream catalog needed lenient decoding",
+		"url", r.logURL, "coercions", len(coercions), "rounding", r.decode.Rounding,
+		"first_index", first.Index, "first_field", first.Field, "first_kind", first.Kind, "first_from", first.From)
+}
+
+func (r *HTTPBookRepository) cachedCatalog() cachedCatalog {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cached
+}
+
+// storeCatalog keeps the decoded books for reuse after a 304, but only when the
+// upstream sent a validator to revalidate them with. Callers get their own
+// copy, so the cached slice can't be modified.
+func (r *HTTPBookRepository) storeCatalog(header http.Header, books []models.Book) {
+	cached := cachedCatalog{
+		etag:         header.Get(headerETag),
+		lastModified: header.Get(headerLastModified),
+	}
+	if cached.etag != "" || cached.lastModified != "" {
+		cached.books = slices.Clone(books)
+		if cached.books == nil {
+			cached.books = []models.Book{}
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cached = cached
+}
+
+func (r *HTTPBookRepository) recordCacheLookup(hit bool) {
+	if r.observer == nil {
+		return
+	}
+	if hit {
+		r.observer.RecordCacheHit()
+	} else {
+		r.observer.RecordCacheMiss()
+	}
+}
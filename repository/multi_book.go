@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"educabot.com/bookshop/models"
+)
+
+const (
+	// MergeByID treats books with the same ID as the same book.
+	MergeByID MergeKey = "id"
+	// MergeByNameAuthor treats books with the same name and author, ignoring
+	// case and surrounding space, as the same book. Providers that number
+	// their books independently should use it; the merged catalog keeps the
+	// winning provider's ID.
+	MergeByNameAuthor MergeKey = "name_author"
+
+	// PreferPriority keeps the book from the provider with the lowest
+	// Priority value.
+	PreferPriority ConflictPolicy = "priority"
+	// PreferFreshest keeps the book from the provider whose catalog changed
+	// most recently, falling back to priority on ties.
+	PreferFreshest ConflictPolicy = "freshest"
+)
+
+var ErrAllProvidersFailed = errors.New("all book providers failed")
+
+type (
+	MergeKey       string
+	ConflictPolicy string
+
+	// Provider is one source of books. Lower Priority values win conflicts
+	// under PreferPriority and break ties under PreferFreshest.
+	Provider struct {
+		Name       string
+		Priority   int
+		Repository BookRepository
+	}
+
+	// FreshnessReporter is implemented by repositories that know when their
+	// catalog last changed. Providers without it count as changed when their
+	// fetch completed.
+	FreshnessReporter interface {
+		CatalogModifiedAt() time.Time
+	}
+
+	// ProviderStatus reports how one provider did on its latest call. Books
+	// counts what it returned and Error holds the ErrorCategory of its
+	// failure, never the error's text, since reports are served on /readyz.
+	// Merged is set by MultiBookRepository, counting the books that made it
+	// into the merged catalog; Serving and RetryAt are set by
+	// FailoverBookRepository.
+	ProviderStatus struct {
+		Name       string        `json:"name"`
+		Priority   int           `json:"priority"`
+		Books      int           `json:"books"`
+		Merged     int           `json:"merged,omitempty"`
+		Duration   time.Duration `json:"duration"`
+		ModifiedAt time.Time     `json:"modified_at,omitzero"`
+		Error      string        `json:"error,omitempty"`
+		Serving    bool          `json:"serving,omitempty"`
+		RetryAt    time.Time     `json:"retry_at,omitzero"`
+	}
+
+	MultiBookRepository struct {
+		providers []Provider
+		key       MergeKey
+		conflicts ConflictPolicy
+
+		mu     sync.Mutex
+		status FetchStatus
+		report []ProviderStatus
+	}
+
+	providerResult struct {
+		books      []models.Book
+		modifiedAt time.Time
+		err        error
+	}
+)
+
+func NewMultiBookRepository(providers []Provider, key MergeKey, conflicts ConflictPolicy) *MultiBookRepository {
+	sorted := slices.Clone(providers)
+	slices.SortStableFunc(sorted, func(a, b Provider) int { return a.Priority - b.Priority })
+	return &MultiBookRepository{providers: sorted, key: key, conflicts: conflicts}
+}
+
+func (r *MultiBookRepository) GetBooks(ctx context.Context) ([]models.Book, error) {
+	books, _, err := r.GetBooksReport(ctx)
+	return books, err
+}
+
+// GetBooksReport fetches from every provider concurrently and merges whatever
+// succeeded. It fails only when every provider does, with an error wrapping
+// ErrAllProvidersFailed and each provider's error.
+func (r *MultiBookRepository) GetBooksReport(ctx context.Context) ([]models.Book, []ProviderStatus, error) {
+	results := make([]providerResult, len(r.providers))
+	report := make([]ProviderStatus, len(r.providers))
+	var wg sync.WaitGroup
+	for i, provider := range r.providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			books, err := provider.Repository.GetBooks(ctx)
+			results[i] = providerResult{books: books, modifiedAt: modifiedAt(provider.Repository), err: err}
+			report[i] = ProviderStatus{
+				Name:       provider.Name,
+				Priority:   provider.Priority,
+				Books:      len(books),
+				Duration:   time.Since(start),
+				ModifiedAt: results[i].modifiedAt,
+			}
+			if err != nil {
+				report[i].Error = ErrorCategory(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var errs []error
+	for i, result := range results {
+		if result.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.providers[i].Name, result.err))
+		}
+	}
+	var err error
+	if len(errs) == len(r.providers) {
+		err = fmt.Errorf("%w: %w", ErrAllProvidersFailed, errors.Join(errs...))
+	}
+
+	var books []models.Book
+	if err == nil {
+		books = r.merge(results, report)
+		RecordSource(ctx, r.contributors(report))
+		if len(errs) > 0 {
+			slog.WarnContext(ctx, "serving books from a subset of providers",
+				"failed", len(errs), "providers", len(r.providers), "error", errors.Join(errs...))
+		}
+	}
+	r.record(report, errors.Join(errs...), err == nil)
+	return books, slices.Clone(report), err
+}
+
+// Report returns the per-provider status of the latest fan-out.
+func (r *MultiBookRepository) Report() []ProviderStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.report)
+}
+
+// FetchStatus counts a fan-out as successful when any provider succeeded;
+// LastError still carries the errors of the providers that failed.
+func (r *MultiBookRepository) FetchStatus() FetchStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status
+}
+
+func (r *MultiBookRepository) record(report []ProviderStatus, err error, succeeded bool) {
+	invalid := 0
+	for _, provider := range r.providers {
+		if reporter, ok := provider.Repository.(interface{ FetchStatus() FetchStatus }); ok {
+			invalid += reporter.FetchStatus().InvalidBooks
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.report = slices.Clone(report)
+	r.status.LastAttempt = time.Now()
+	r.status.LastError = err
+	r.status.InvalidBooks = invalid
+	if succeeded {
+		r.status.LastSuccess = r.status.LastAttempt
+	}
+}
+
+// merge walks providers in priority order, so the first occurrence of a key
+// fixes its position in the result and PreferPriority never has to replace.
+func (r *MultiBookRepository) merge(results []providerResult, report []ProviderStatus) []models.Book {
+	type winner struct {
+		position int
+		provider int
+	}
+	var merged []models.Book
+	winners := make(map[string]winner)
+	for i, result := range results {
+		if result.err != nil {
+			continue
+		}
+		for _, book := range result.books {
+			key := r.identity(book)
+			current, seen := winners[key]
+			switch {
+			case !seen:
+				winners[key] = winner{position: len(merged), provider: i}
+				merged = append(merged, book)
+				report[i].Merged++
+			case current.provider != i && r.conflicts == PreferFreshest &&
+				result.modifiedAt.After(results[current.provider].modifiedAt):
+				merged[current.position] = book
+				report[current.provider].Merged--
+				report[i].Merged++
+				winners[key] = winner{position: current.position, provider: i}
+			}
+		}
+	}
+	return merged
+}
+
+func (r *MultiBookRepository) contributors(report []ProviderStatus) string {
+	var names []string
+	for _, provider := range report {
+		if provider.Merged > 0 {
+			names = append(names, provider.Name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+func (r *MultiBookRepository) identity(book models.Book) string {
+	if r.key == MergeByNameAuthor {
+		return strings.ToLower(strings.TrimSpace(book.Name)) + "\x00" + strings.ToLower(strings.TrimSpace(book.Author))
+	}
+	return fmt.Sprint(book.ID)
+}
+
+func modifiedAt(repo BookRepository) time.Time {
+	if reporter, ok := repo.(FreshnessReporter); ok {
+		if modifiedAt := reporter.CatalogModifiedAt(); !modifiedAt.IsZero() {
+			return modifiedAt
+		}
+	}
+	return time.Now()
+}
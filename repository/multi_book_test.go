@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/models"
+	"educabot.com/bookshop/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+var errProviderDown = errors.New("provider down")
+
+type freshRepository struct {
+	*mocks.MockBookRepository
+	modifiedAt time.Time
+}
+
+func (r freshRepository) CatalogModifiedAt() time.Time { return r.modifiedAt }
+
+func providerBooks(books ...models.Book) *mocks.MockBookRepository {
+	return mocks.NewMockBookRepository().WithBooks(books)
+}
+
+var (
+	duneCheap     = models.Book{ID: 1, Name: "Dune", Author: "Frank Herbert", UnitsSold: 100, Price: 10}
+	duneExpensive = models.Book{ID: 7, Name: " dune ", Author: "FRANK HERBERT", UnitsSold: 100, Price: 30}
+	hobbit        = models.Book{ID: 2, Name: "The Hobbit", Author: "J.R.R. Tolkien", UnitsSold: 200, Price: 15}
+)
+
+func TestMultiBookRepository_MergesByPriority(t *testing.T) {
+	repo := NewMultiBookRepository([]Provider{
+		{Name: "backup", Priority: 2, Repository: providerBooks(models.Book{ID: 1, Name: "Dune (backup)"}, hobbit)},
+		{Name: "primary", Priority: 1, Repository: providerBooks(duneCheap)},
+	}, MergeByID, PreferPriority)
+
+	books, report, err := repo.GetBooksReport(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, []models.Book{duneCheap, hobbit}, books)
+	require.Equal(t, "primary", report[0].Name)
+	require.Equal(t, 1, report[0].Merged)
+	require.Equal(t, 2, report[1].Books)
+	require.Equal(t, 1, report[1].Merged)
+}
+
+func TestMultiBookRepository_MergesByNameAndAuthor(t *testing.T) {
+	repo := NewMultiBookRepository([]Provider{
+		{Name: "primary", Priority: 1, Repository: providerBooks(duneCheap)},
+		{Name: "backup", Priority: 2, Repository: providerBooks(duneExpensive, hobbit)},
+	}, MergeByNameAuthor, PreferPriority)
+
+	books, err := repo.GetBooks(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, []models.Book{duneCheap, hobbit}, books)
+}
+
+func TestMultiBookRepository_PrefersFreshest(t *testing.T) {
+	now := time.Now()
+	repo := NewMultiBookRepository([]Provider{
+		{Name: "primary", Priority: 1, Repository: freshRepository{providerBooks(duneCheap, hobbit), now.Add(-time.Hour)}},
+		{Name: "backup", Priority: 2, Repository: freshRepository{providerBooks(duneExpensive), now}},
+	}, MergeByNameAuthor, PreferFreshest)
+
+	books, report, err := repo.GetBooksReport(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, []models.Book{duneExpensive, hobbit}, books)
+	require.Equal(t, 1, report[0].Merged)
+	require.Equal(t, 1, report[1].Merged)
+}
+
+func TestMultiBookRepository_ToleratesPartialFailure(t *testing.T) {
+	repo := NewMultiBookRepository([]Provider{
+		{Name: "primary", Priority: 1, Repository: mocks.NewMockBookRepository().WithError(errProviderDown)},
+		{Name: "backup", Priority: 2, Repository: providerBooks(hobbit)},
+	}, MergeByID, PreferPriority)
+
+	books, report, err := repo.GetBooksReport(context.Background())
+	status := repo.FetchStatus()
+
+	require.NoError(t, err)
+	require.Equal(t, []models.Book{hobbit}, books)
+	require.Equal(t, ErrorCategoryOther, report[0].Error)
+	require.Empty(t, report[1].Error)
+	require.Equal(t, report, repo.Report())
+	require.False(t, status.LastSuccess.IsZero())
+	require.ErrorIs(t, status.LastError, errProviderDown)
+}
+
+func TestMultiBookRepository_AllProvidersFailed(t *testing.T) {
+	errOther := errors.New("other down")
+	repo := NewMultiBookRepository([]Provider{
+		{Name: "primary", Priority: 1, Repository: mocks.NewMockBookRepository().WithError(errProviderDown)},
+		{Name: "backup", Priority: 2, Repository: mocks.NewMockBookRepository().WithError(errOther)},
+	}, MergeByID, PreferPriority)
+
+	books, err := repo.GetBooks(context.Background())
+
+	require.Nil(t, books)
+	require.ErrorIs(t, err, ErrAllProvidersFailed)
+	require.ErrorIs(t, err, errProviderDown)
+	require.ErrorIs(t, err, errOther)
+	require.True(t, repo.FetchStatus().LastSuccess.IsZero())
+}
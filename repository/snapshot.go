@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"educabot.com/bookshop/models"
+)
+
+const (
+	// SnapshotSchemaVersion changes whenever the snapshot file format does.
+	// Files with another version are ignored rather than migrated.
+	SnapshotSchemaVersion = 1
+
+	SnapshotSource = "snapshot"
+
+	defaultSnapshotSource = "upstream"
+	checksumPrefix        = "sha256:"
+)
+
+var (
+	ErrSnapshotNotFound    = errors.New("snapshot not found")
+	ErrSnapshotCorrupt     = errors.New("snapshot corrupt")
+	ErrSnapshotVersion     = errors.New("unsupported snapshot schema version")
+	ErrWritingSnapshotFile = errors.New("writing snapshot file")
+)
+
+type (
+	// Snapshot is a catalog as persisted on disk. Checksum covers Books, so a
+	// truncated or hand-edited file is detected on load.
+	Snapshot struct {
+		SchemaVersion int           `json:"schema_version"`
+		SavedAt       time.Time     `json:"saved_at"`
+		Source        string        `json:"source"`
+		Checksum      string        `json:"checksum"`
+		Books         []models.Book `json:"books"`
+	}
+
+	SnapshotStore struct {
+		path string
+	}
+
+	// SnapshotBookRepository persists every catalog its inner repository
+	// returns that differs from the last one saved, and serves the saved
+	// catalog while the inner repository fails.
+	SnapshotBookRepository struct {
+		inner BookRepository
+		store *SnapshotStore
+
+		mu       sync.Mutex
+		snapshot Snapshot
+		loaded   bool
+	}
+)
+
+func NewSnapshotStore(path string) *SnapshotStore {
+	return &SnapshotStore{path: path}
+}
+
+// Save writes the snapshot to a temporary file in the same directory and
+// renames it into place, so readers never see a partial file.
+func (s *SnapshotStore) Save(snapshot Snapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrWritingSnapshotFile, err)
+	}
+	dir := filepath.Dir(s.path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrWritingSnapshotFile, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("%w: %w", ErrWritingSnapshotFile, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("%w: %w", ErrWritingSnapshotFile, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("%w: %w", ErrWritingSnapshotFile, err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("%w: %w", ErrWritingSnapshotFile, err)
+	}
+	syncDir(dir)
+	return nil
+}
+
+func (s *SnapshotStore) Load() (Snapshot, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Snapshot{}, ErrSnapshotNotFound
+	}
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("%w: %w", ErrSnapshotCorrupt, err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return Snapshot{}, fmt.Errorf("%w: %w", ErrSnapshotCorrupt, err)
+	}
+	if snapshot.SchemaVersion != SnapshotSchemaVersion {
+		return Snapshot{}, fmt.Errorf("%w: %d", ErrSnapshotVersion, snapshot.SchemaVersion)
+	}
+	checksum, err := catalogChecksum(snapshot.Books)
+	if err != nil || checksum != snapshot.Checksum {
+		return Snapshot{}, fmt.Errorf("%w: checksum mismatch", ErrSnapshotCorrupt)
+	}
+	return snapshot, nil
+}
+
+func NewSnapshotBookRepository(inner BookRepository, store *SnapshotStore) *SnapshotBookRepository {
+	return &SnapshotBookRepository{inner: inner, store: store}
+}
+
+// Load reads the persisted snapshot so it can be served before the inner
+// repository has answered. A missing file is not an error.
+func (r *SnapshotBookRepository) Load() (Snapshot, bool, error) {
+	snapshot, err := r.store.Load()
+	if errors.Is(err, ErrSnapshotNotFound) {
+		return Snapshot{}, false, nil
+	}
+	if err != nil {
+		return Snapshot{}, false, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.snapshot = snapshot
+	r.loaded = true
+	return snapshot, true, nil
+}
+
+func (r *SnapshotBookRepository) GetBooks(ctx context.Context) ([]models.Book, error) {
+	innerCtx, source := WithSource(ctx)
+	books, err := r.inner.GetBooks(innerCtx)
+	if err != nil {
+		snapshot, ok := r.Snapshot()
+		if !ok {
+			return nil, err
+		}
+		slog.WarnContext(ctx, "serving catalog snapshot",
+			"saved_at", snapshot.SavedAt, "source", snapshot.Source, "error", err)
+		RecordSource(ctx, SnapshotSource)
+		return slices.Clone(snapshot.Books), nil
+	}
+
+	provider := source.Provider()
+	RecordSource(ctx, provider)
+	if provider == "" {
+		provider = defaultSnapshotSource
+	}
+	r.save(ctx, books, provider)
+	return books, nil
+}
+
+// Snapshot returns a copy of the catalog last saved or loaded.
+func (r *SnapshotBookRepository) Snapshot() (Snapshot, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	snapshot := r.snapshot
+	snapshot.Books = slices.Clone(snapshot.Books)
+	return snapshot, r.loaded
+}
+
+// save skips unchanged catalogs so a steady upstream doesn't rewrite the file
+// on every poll. A failed write is logged and otherwise ignored: the books
+// were fetched fine, only the fallback is stale.
+func (r *SnapshotBookRepository) save(ctx context.Context, books []models.Book, source string) {
+	checksum, err := catalogChecksum(books)
+	if err != nil {
+		slog.WarnContext(ctx, "checksumming catalog snapshot failed", "error", err)
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.loaded && r.snapshot.Checksum == checksum && r.snapshot.Source == source {
+		return
+	}
+
+	snapshot := Snapshot{
+		SchemaVersion: SnapshotSchemaVersion,
+		SavedAt:       time.Now().UTC(),
+		Source:        source,
+		Checksum:      checksum,
+		Books:         slices.Clone(books),
+	}
+	if err := r.store.Save(snapshot); err != nil {
+		slog.WarnContext(ctx, "saving catalog snapshot failed", "path", r.store.path, "error", err)
+		return
+	}
+	r.snapshot = snapshot
+	r.loaded = true
+	slog.DebugContext(ctx, "saved catalog snapshot", "path", r.store.path, "books", len(books), "source", source)
+}
+
+func catalogChecksum(books []models.Book) (string, error) {
+	if books == nil {
+		books = []models.Book{}
+	}
+	data, err := json.Marshal(books)
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256(data)
+	return checksumPrefix + hex.EncodeToString(digest[:]), nil
+}
+
+// syncDir makes the rename durable. It is best effort because not every
+// platform lets a directory be opened for syncing.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	defer d.Close()
+	d.Sync()
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"educabot.com/bookshop/models"
+	"educabot.com/bookshop/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSnapshotStore(t *testing.T) *SnapshotStore {
+	return NewSnapshotStore(filepath.Join(t.TempDir(), "catalog-snapshot.json"))
+}
+
+func TestSnapshotStore_SaveAndLoad(t *testing.T) {
+	store := newTestSnapshotStore(t)
+	repo := NewSnapshotBookRepository(providerBooks(duneCheap, hobbit), store)
+
+	_, err := repo.GetBooks(context.Background())
+	require.NoError(t, err)
+	loaded, err := store.Load()
+
+	require.NoError(t, err)
+	require.Equal(t, SnapshotSchemaVersion, loaded.SchemaVersion)
+	require.Equal(t, []models.Book{duneCheap, hobbit}, loaded.Books)
+	require.Equal(t, defaultSnapshotSource, loaded.Source)
+	require.Regexp(t, `^sha256:[0-9a-f]{64}$`, loaded.Checksum)
+	entries, err := os.ReadDir(filepath.Dir(store.path))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+func TestSnapshotStore_LoadRejectsBadFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(snapshot *Snapshot)
+		wantErr error
+	}{
+		{name: "checksum mismatch", mutate: func(s *Snapshot) { s.Books[0].Price = 1 }, wantErr: ErrSnapshotCorrupt},
+		{name: "other schema version", mutate: func(s *Snapshot) { s.SchemaVersion = SnapshotSchemaVersion + 1 }, wantErr: ErrSnapshotVersion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestSnapshotStore(t)
+			checksum, err := catalogChecksum([]models.Book{duneCheap})
+			require.NoError(t, err)
+			snapshot := Snapshot{SchemaVersion: SnapshotSchemaVersion, Checksum: checksum, Books: []models.Book{duneCheap}}
+			tt.mutate(&snapshot)
+			require.NoError(t, store.Save(snapshot))
+
+			_, err = store.Load()
+
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestSnapshotStore_LoadTruncatedFile(t *testing.T) {
+	store := newTestSnapshotStore(t)
+	data, err := json.Marshal(Snapshot{SchemaVersion: SnapshotSchemaVersion, Books: []models.Book{duneCheap}})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(store.path, data[:len(data)/2], 0o600))
+
+	_, err = store.Load()
+
+	require.ErrorIs(t, err, ErrSnapshotCorrupt)
+}
+
+func TestSnapshotBookRepository_LoadMissingSnapshot(t *testing.T) {
+	repo := NewSnapshotBookRepository(providerBooks(hobbit), newTestSnapshotStore(t))
+
+	_, ok, err := repo.Load()
+
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestSnapshotBookRepository_ServesSnapshotWhileUpstreamFails(t *testing.T) {
+	store := newTestSnapshotStore(t)
+	_, err := NewSnapshotBookRepository(providerBooks(duneCheap), store).GetBooks(context.Background())
+	require.NoError(t, err)
+	repo := NewSnapshotBookRepository(mocks.NewMockBookRepository().WithError(errProviderDown), store)
+	_, loaded, err := repo.Load()
+	require.NoError(t, err)
+	ctx, source := WithSource(context.Background())
+
+	books, err := repo.GetBooks(ctx)
+
+	require.True(t, loaded)
+	require.NoError(t, err)
+	require.Equal(t, []models.Book{duneCheap}, books)
+	require.Equal(t, SnapshotSource, source.Provider())
+}
+
+func TestSnapshotBookRepository_FailsWithoutSnapshot(t *testing.T) {
+	repo := NewSnapshotBookRepository(mocks.NewMockBookRepository().WithError(errProviderDown), newTestSnapshotStore(t))
+
+	_, err := repo.GetBooks(context.Background())
+
+	require.ErrorIs(t, err, errProviderDown)
+}
+
+func TestSnapshotBookRepository_RecordsServingProvider(t *testing.T) {
+	store := newTestSnapshotStore(t)
+	failover := NewFailoverBookRepository([]Provider{
+		{Name: "primary", Priority: 1, Repository: mocks.NewMockBookRepository().WithError(errProviderDown)},
+		{Name: "backup", Priority: 2, Repository: providerBooks(hobbit)},
+	}, testCooldown)
+	repo := NewSnapshotBookRepository(failover, store)
+	ctx, source := WithSource(context.Background())
+
+	_, err := repo.GetBooks(ctx)
+	snapshot, ok := repo.Snapshot()
+
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "backup", snapshot.Source)
+	require.Equal(t, "backup", source.Provider())
+}
+
+func TestSnapshotBookRepository_SkipsUnchangedCatalog(t *testing.T) {
+	store := newTestSnapshotStore(t)
+	upstream := providerBooks(duneCheap)
+	repo := NewSnapshotBookRepository(upstream, store)
+
+	_, err := repo.GetBooks(context.Background())
+	require.NoError(t, err)
+	first, _ := repo.Snapshot()
+	_, err = repo.GetBooks(context.Background())
+	require.NoError(t, err)
+	unchanged, _ := repo.Snapshot()
+	upstream.WithBooks([]models.Book{hobbit})
+	_, err = repo.GetBooks(context.Background())
+	require.NoError(t, err)
+	changed, _ := repo.Snapshot()
+
+	require.Equal(t, first.SavedAt, unchanged.SavedAt)
+	require.NotEqual(t, first.Checksum, changed.Checksum)
+	require.Equal(t, []models.Book{hobbit}, changed.Books)
+}
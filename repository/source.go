@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"sync"
+)
+
+type (
+	// Source collects which provider served the books for a request. Pass the
+	// context from WithSource down to GetBooks and read Provider afterwards.
+	Source struct {
+		mu       sync.Mutex
+		provider string
+	}
+
+	sourceKey struct{}
+)
+
+func WithSource(ctx context.Context) (context.Context, *Source) {
+	source := &Source{}
+	return context.WithValue(ctx, sourceKey{}, source), source
+}
+
+func (s *Source) Provider() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.provider
+}
+
+// RecordSource names the provider that served ctx's books unless a nested
+// repository already did, so the most specific name wins.
+func RecordSource(ctx context.Context, provider string) {
+	source, ok := ctx.Value(sourceKey{}).(*Source)
+	if !ok {
+		return
+	}
+	source.mu.Lock()
+	defer source.mu.Unlock()
+	if source.provider == "" {
+		source.provider = provider
+	}
+}
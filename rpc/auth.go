@@ -0,0 +1,60 @@
+package rpc
+
+import (
+	"context"
+	"net/http"
+
+	"educabot.com/bookshop/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryAuthenticate rejects calls the authenticator cannot identify with
+// Unauthenticated, and calls whose principal lacks scope with
+// PermissionDenied. Credentials are read from request metadata under the same
+// names as the HTTP headers, such as "x-api-key" or "authorization".
+func UnaryAuthenticate(authenticator auth.Authenticator, scope string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, authenticator, scope)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthenticate is UnaryAuthenticate for streaming calls.
+func StreamAuthenticate(authenticator auth.Authenticator, scope string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(stream.Context(), authenticator, scope)
+		if err != nil {
+			return err
+		}
+		return handler(srv, contextStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// authenticate hands the call's metadata to the authenticator as request
+// headers, so the HTTP authenticators work unchanged, and stores the
+// principal on the returned context.
+func authenticate(ctx context.Context, authenticator auth.Authenticator, scope string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	header := make(http.Header, len(md))
+	for key, values := range md {
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+	req := (&http.Request{Method: http.MethodPost, Header: header}).WithContext(ctx)
+
+	principal, err := authenticator.Authenticate(req)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if !principal.HasScope(scope) {
+		return nil, status.Errorf(codes.PermissionDenied, "missing scope %s", scope)
+	}
+	return auth.WithPrincipal(ctx, principal), nil
+}
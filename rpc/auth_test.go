@@ -0,0 +1,78 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"educabot.com/bookshop/auth"
+	"educabot.com/bookshop/models"
+	bookshopv1 "educabot.com/bookshop/proto/bookshop/v1"
+	"educabot.com/bookshop/test/mocks"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testReaderKey = "reader-key"
+	testWriterKey = "writer-key"
+)
+
+func newAuthenticatedTestClient(t *testing.T) bookshopv1.BookMetricsClient {
+	authenticator, err := auth.NewAPIKeyAuthenticator([]auth.APIKey{
+		{Name: "reader", Hash: auth.HashAPIKey(testReaderKey), Scopes: []string{auth.ScopeBooksRead}},
+		{Name: "writer", Hash: auth.HashAPIKey(testWriterKey), Scopes: []string{auth.ScopeBooksWrite}},
+	})
+	require.NoError(t, err)
+	metricsSvc := mocks.NewMockMetricsService().WithMeanUnitsSold(testMeanUnitsSold)
+	booksSvc := mocks.NewMockBooksService().WithBooks([]models.Book{{Name: testBookLion}})
+	return newTestClient(t, metricsSvc, booksSvc,
+		grpc.UnaryInterceptor(UnaryAuthenticate(authenticator, auth.ScopeBooksRead)),
+		grpc.StreamInterceptor(StreamAuthenticate(authenticator, auth.ScopeBooksRead)),
+	)
+}
+
+func withAPIKey(key string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "x-api-key", key)
+}
+
+func TestUnaryAuthenticate(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		code codes.Code
+	}{
+		{name: "missing credentials", ctx: context.Background(), code: codes.Unauthenticated},
+		{name: "invalid key", ctx: withAPIKey("wrong"), code: codes.Unauthenticated},
+		{name: "missing scope", ctx: withAPIKey(testWriterKey), code: codes.PermissionDenied},
+		{name: "authorized", ctx: withAPIKey(testReaderKey), code: codes.OK},
+	}
+	client := newAuthenticatedTestClient(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.GetMeanUnitsSold(tt.ctx, &bookshopv1.GetMeanUnitsSoldRequest{})
+
+			require.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
+
+func TestStreamAuthenticate(t *testing.T) {
+	client := newAuthenticatedTestClient(t)
+
+	rejected, err := client.ListBooks(context.Background(), &bookshopv1.ListBooksRequest{})
+	require.NoError(t, err)
+	_, err = rejected.Recv()
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	accepted, err := client.ListBooks(withAPIKey(testReaderKey), &bookshopv1.ListBooksRequest{})
+	require.NoError(t, err)
+	book, err := accepted.Recv()
+	require.NoError(t, err)
+	require.Equal(t, testBookLion, book.GetName())
+	_, err = accepted.Recv()
+	require.ErrorIs(t, err, io.EOF)
+}
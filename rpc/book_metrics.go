@@ -0,0 +1,69 @@
+package rpc
+
+import (
+	"context"
+
+	"educabot.com/bookshop/models"
+	bookshopv1 "educabot.com/bookshop/proto/bookshop/v1"
+	"educabot.com/bookshop/service"
+	"google.golang.org/grpc"
+)
+
+type bookMetricsServer struct {
+	bookshopv1.UnimplementedBookMetricsServer
+	metricsService service.MetricsService
+	booksService   service.BooksService
+}
+
+func NewBookMetricsServer(metricsService service.MetricsService, booksService service.BooksService) bookshopv1.BookMetricsServer {
+	return &bookMetricsServer{metricsService: metricsService, booksService: booksService}
+}
+
+func (s *bookMetricsServer) GetMeanUnitsSold(ctx context.Context, _ *bookshopv1.GetMeanUnitsSoldRequest) (*bookshopv1.GetMeanUnitsSoldResponse, error) {
+	mean, err := s.metricsService.GetMeanUnitsSold(ctx)
+	if err != nil {
+		return nil, mapErrorToStatus(ctx, err)
+	}
+	return &bookshopv1.GetMeanUnitsSoldResponse{MeanUnitsSold: uint64(mean)}, nil
+}
+
+func (s *bookMetricsServer) GetCheapestBook(ctx context.Context, _ *bookshopv1.GetCheapestBookRequest) (*bookshopv1.GetCheapestBookResponse, error) {
+	book, err := s.metricsService.GetCheapestBook(ctx)
+	if err != nil {
+		return nil, mapErrorToStatus(ctx, err)
+	}
+	return &bookshopv1.GetCheapestBookResponse{Book: toProtoBook(book)}, nil
+}
+
+func (s *bookMetricsServer) GetBooksCountByAuthor(ctx context.Context, req *bookshopv1.GetBooksCountByAuthorRequest) (*bookshopv1.GetBooksCountByAuthorResponse, error) {
+	count, err := s.metricsService.GetBooksCountByAuthor(ctx, req.GetAuthor())
+	if err != nil {
+		return nil, mapErrorToStatus(ctx, err)
+	}
+	return &bookshopv1.GetBooksCountByAuthorResponse{Count: uint64(count)}, nil
+}
+
+// ListBooks streams every matching book from one catalog fetch, so a stream
+// never mixes catalog versions.
+func (s *bookMetricsServer) ListBooks(req *bookshopv1.ListBooksRequest, stream grpc.ServerStreamingServer[bookshopv1.Book]) error {
+	books, err := s.booksService.FilterBooks(stream.Context(), models.BooksFilter{Author: req.GetAuthor()})
+	if err != nil {
+		return mapErrorToStatus(stream.Context(), err)
+	}
+	for _, book := range books {
+		if err := stream.Send(toProtoBook(book)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toProtoBook(book models.Book) *bookshopv1.Book {
+	return &bookshopv1.Book{
+		Id:        uint64(book.ID),
+		Name:      book.Name,
+		Author:    book.Author,
+		UnitsSold: uint64(book.UnitsSold),
+		Price:     uint64(book.Price),
+	}
+}
@@ -0,0 +1,166 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"educabot.com/bookshop/models"
+	bookshopv1 "educabot.com/bookshop/proto/bookshop/v1"
+	"educabot.com/bookshop/service"
+	"educabot.com/bookshop/test/mocks"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	bufferSize   = 1024 * 1024
+	bufnetTarget = "passthrough:///bufnet"
+
+	testAuthorTolkien = "J.R.R. Tolkien"
+	testBookLion      = "The Lion, the Witch and the Wardrobe"
+	testBookTowers    = "The Two Towers"
+	testMeanUnitsSold = uint(53750000)
+	testBooksCount    = uint(3)
+	testCheapestPrice = uint(15)
+	testBookID        = uint(4)
+)
+
+func newTestClient(t *testing.T, metricsSvc service.MetricsService, booksSvc service.BooksService, opts ...grpc.ServerOption) bookshopv1.BookMetricsClient {
+	listener := bufconn.Listen(bufferSize)
+	server := grpc.NewServer(opts...)
+	bookshopv1.RegisterBookMetricsServer(server, NewBookMetricsServer(metricsSvc, booksSvc))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(bufnetTarget,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return bookshopv1.NewBookMetricsClient(conn)
+}
+
+func TestGetMeanUnitsSold_Success(t *testing.T) {
+	client := newTestClient(t, mocks.NewMockMetricsService().WithMeanUnitsSold(testMeanUnitsSold), mocks.NewMockBooksService())
+
+	resp, err := client.GetMeanUnitsSold(context.Background(), &bookshopv1.GetMeanUnitsSoldRequest{})
+
+	require.NoError(t, err)
+	require.Equal(t, uint64(testMeanUnitsSold), resp.GetMeanUnitsSold())
+}
+
+func TestGetMeanUnitsSold_NoBooksFound(t *testing.T) {
+	client := newTestClient(t, mocks.NewMockMetricsService().WithError(service.ErrNoBooksFound), mocks.NewMockBooksService())
+
+	_, err := client.GetMeanUnitsSold(context.Background(), &bookshopv1.GetMeanUnitsSoldRequest{})
+
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGetCheapestBook_Success(t *testing.T) {
+	book := models.Book{ID: testBookID, Name: testBookLion, Price: testCheapestPrice}
+	client := newTestClient(t, mocks.NewMockMetricsService().WithCheapestBook(book), mocks.NewMockBooksService())
+
+	resp, err := client.GetCheapestBook(context.Background(), &bookshopv1.GetCheapestBookRequest{})
+
+	require.NoError(t, err)
+	require.Equal(t, uint64(testBookID), resp.GetBook().GetId())
+	require.Equal(t, testBookLion, resp.GetBook().GetName())
+	require.Equal(t, uint64(testCheapestPrice), resp.GetBook().GetPrice())
+}
+
+func TestGetCheapestBook_FetchingError(t *testing.T) {
+	client := newTestClient(t, mocks.NewMockMetricsService().WithError(service.ErrFetchingBooks), mocks.NewMockBooksService())
+
+	_, err := client.GetCheapestBook(context.Background(), &bookshopv1.GetCheapestBookRequest{})
+
+	require.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestGetBooksCountByAuthor_Success(t *testing.T) {
+	client := newTestClient(t, mocks.NewMockMetricsService().WithBooksCount(testBooksCount), mocks.NewMockBooksService())
+
+	resp, err := client.GetBooksCountByAuthor(context.Background(), &bookshopv1.GetBooksCountByAuthorRequest{Author: testAuthorTolkien})
+
+	require.NoError(t, err)
+	require.Equal(t, uint64(testBooksCount), resp.GetCount())
+}
+
+func TestGetBooksCountByAuthor_AuthorNotFound(t *testing.T) {
+	client := newTestClient(t, mocks.NewMockMetricsService().WithError(service.ErrAuthorNotFound), mocks.NewMockBooksService())
+
+	_, err := client.GetBooksCountByAuthor(context.Background(), &bookshopv1.GetBooksCountByAuthorRequest{Author: testAuthorTolkien})
+
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestListBooks_StreamsAllBooks(t *testing.T) {
+	booksSvc := mocks.NewMockBooksService().WithBooks([]models.Book{{Name: testBookTowers}, {Name: testBookLion}})
+	client := newTestClient(t, mocks.NewMockMetricsService(), booksSvc)
+
+	stream, err := client.ListBooks(context.Background(), &bookshopv1.ListBooksRequest{})
+	require.NoError(t, err)
+	var names []string
+	for {
+		book, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, book.GetName())
+	}
+
+	require.Equal(t, []string{testBookTowers, testBookLion}, names)
+}
+
+type countingRepository struct {
+	books []models.Book
+	calls int
+}
+
+func (r *countingRepository) GetBooks(context.Context) ([]models.Book, error) {
+	r.calls++
+	return r.books, nil
+}
+
+func TestListBooks_FetchesCatalogOnce(t *testing.T) {
+	repo := &countingRepository{}
+	for id := range 2*service.MaxPageSize + 1 {
+		repo.books = append(repo.books, models.Book{ID: uint(id), Author: testAuthorTolkien})
+	}
+	client := newTestClient(t, mocks.NewMockMetricsService(), service.NewBooksService(repo))
+
+	stream, err := client.ListBooks(context.Background(), &bookshopv1.ListBooksRequest{Author: testAuthorTolkien})
+	require.NoError(t, err)
+	received := 0
+	for {
+		_, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		received++
+	}
+
+	require.Equal(t, len(repo.books), received)
+	require.Equal(t, 1, repo.calls)
+}
+
+func TestListBooks_FetchingError(t *testing.T) {
+	client := newTestClient(t, mocks.NewMockMetricsService(), mocks.NewMockBooksService().WithError(service.ErrFetchingBooks))
+
+	stream, err := client.ListBooks(context.Background(), &bookshopv1.ListBooksRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+
+	require.Equal(t, codes.Unavailable, status.Code(err))
+}
@@ -0,0 +1,55 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"educabot.com/bookshop/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const internalErrorMessage = "internal server error"
+
+// serverErrorMessages replaces the text of errors the caller can do nothing
+// about, which can carry upstream URLs or connection details, with one fixed
+// message per code. Other codes keep the error's own text.
+var serverErrorMessages = map[codes.Code]string{
+	codes.Canceled:         "request canceled",
+	codes.DeadlineExceeded: "deadline exceeded",
+	codes.Unavailable:      "book catalog is unavailable",
+	codes.Internal:         internalErrorMessage,
+}
+
+// mapErrorToStatus converts a service error into a gRPC status. Server-side
+// failures are logged with their cause, since the caller only sees the fixed
+// message.
+func mapErrorToStatus(ctx context.Context, err error) error {
+	code := mapErrorToCode(err)
+	message, ok := serverErrorMessages[code]
+	if !ok {
+		return status.Error(code, err.Error())
+	}
+	if code == codes.Unavailable || code == codes.Internal {
+		slog.ErrorContext(ctx, "rpc failed", "code", code.String(), "error", err)
+	}
+	return status.Error(code, message)
+}
+
+func mapErrorToCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, service.ErrInvalidPagination), errors.Is(err, service.ErrInvalidSort):
+		return codes.InvalidArgument
+	case errors.Is(err, service.ErrNoBooksFound), errors.Is(err, service.ErrAuthorNotFound), errors.Is(err, service.ErrBookNotFound):
+		return codes.NotFound
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	case errors.Is(err, service.ErrFetchingBooks):
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
@@ -0,0 +1,50 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	bookshopv1 "educabot.com/bookshop/proto/bookshop/v1"
+	"educabot.com/bookshop/service"
+	"educabot.com/bookshop/test/mocks"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type recordingCallObserver struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (o *recordingCallObserver) ObserveRPC(method, code string, _ time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.calls = append(o.calls, method+" "+code)
+}
+
+func TestInstrument_RecordsMethodAndCode(t *testing.T) {
+	observer := &recordingCallObserver{}
+	client := newTestClient(t, mocks.NewMockMetricsService().WithError(service.ErrNoBooksFound), mocks.NewMockBooksService(),
+		grpc.UnaryInterceptor(UnaryInstrument(observer)),
+		grpc.StreamInterceptor(StreamInstrument(observer)),
+	)
+
+	client.GetMeanUnitsSold(context.Background(), &bookshopv1.GetMeanUnitsSoldRequest{})
+	stream, err := client.ListBooks(context.Background(), &bookshopv1.ListBooksRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.ErrorIs(t, err, io.EOF)
+
+	require.Eventually(t, func() bool {
+		observer.mu.Lock()
+		defer observer.mu.Unlock()
+		return len(observer.calls) == 2
+	}, time.Second, time.Millisecond)
+	require.Equal(t, []string{
+		testMeanUnitsSoldMethod + " NotFound",
+		"/bookshop.v1.BookMetrics/ListBooks OK",
+	}, observer.calls)
+}
@@ -0,0 +1,60 @@
+package rpc
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryLogCalls writes one record per call once the handler has finished.
+// Server-side failures log at error level and caller errors at warning level,
+// so sampling never drops them.
+func UnaryLogCalls(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(ctx, logger, info.FullMethod, status.Code(err), time.Since(start))
+		return resp, err
+	}
+}
+
+// StreamLogCalls is UnaryLogCalls for streaming calls.
+func StreamLogCalls(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, stream)
+		logCall(stream.Context(), logger, info.FullMethod, status.Code(err), time.Since(start))
+		return err
+	}
+}
+
+func logCall(ctx context.Context, logger *slog.Logger, method string, code codes.Code, latency time.Duration) {
+	level := slog.LevelInfo
+	switch {
+	case isServerError(code):
+		level = slog.LevelError
+	case code != codes.OK:
+		level = slog.LevelWarn
+	}
+	logger.LogAttrs(ctx, level, "rpc served",
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Float64("latency_ms", float64(latency.Microseconds())/1000),
+		slog.String("client_ip", peerIP(ctx)),
+	)
+}
+
+// isServerError reports whether code means the server failed the call, as
+// opposed to the caller sending a bad or unauthorized one.
+func isServerError(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal, codes.Unavailable, codes.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,48 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"educabot.com/bookshop/logging"
+	bookshopv1 "educabot.com/bookshop/proto/bookshop/v1"
+	"educabot.com/bookshop/service"
+	"educabot.com/bookshop/test/mocks"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestUnaryLogCalls_RecordsCallDetails(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		code  string
+		level string
+	}{
+		{name: "ok", code: "OK", level: "INFO"},
+		{name: "caller error", err: service.ErrNoBooksFound, code: "NotFound", level: "WARN"},
+		{name: "server error", err: service.ErrFetchingBooks, code: "Unavailable", level: "ERROR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			metricsSvc := mocks.NewMockMetricsService().WithMeanUnitsSold(testMeanUnitsSold)
+			if tt.err != nil {
+				metricsSvc.WithError(tt.err)
+			}
+			client := newTestClient(t, metricsSvc, mocks.NewMockBooksService(),
+				grpc.UnaryInterceptor(UnaryLogCalls(logging.NewLogger(&out, logging.Config{Level: slog.LevelInfo}))),
+			)
+
+			client.GetMeanUnitsSold(context.Background(), &bookshopv1.GetMeanUnitsSoldRequest{})
+
+			record := decodeLogRecord(t, &out)
+			require.Equal(t, tt.level, record["level"])
+			require.Equal(t, testMeanUnitsSoldMethod, record["method"])
+			require.Equal(t, tt.code, record["code"])
+			require.Contains(t, record, "latency_ms")
+		})
+	}
+}
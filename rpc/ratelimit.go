@@ -0,0 +1,113 @@
+package rpc
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net"
+	"strconv"
+
+	"educabot.com/bookshop/auth"
+	"educabot.com/bookshop/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	metadataRetryAfter = "retry-after"
+
+	rateLimitedMessage = "rate limit exceeded"
+
+	// preAuthKeyPrefix matches the HTTP API's, so a client guessing
+	// credentials over both protocols draws on one bucket.
+	preAuthKeyPrefix = "auth|"
+)
+
+// UnaryRateLimit throttles each client, identified by its authenticated
+// principal or otherwise by its IP, rejecting calls over limit with
+// ResourceExhausted. Keys match the HTTP API's, so a client sharing a store
+// with it has one quota across both.
+func UnaryRateLimit(store ratelimit.Store, limit ratelimit.Limit) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := takeToken(ctx, store, rateLimitClient(ctx), limit); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamRateLimit is UnaryRateLimit for streaming calls. A stream costs one
+// token when it opens, however many messages it carries.
+func StreamRateLimit(store ratelimit.Store, limit ratelimit.Limit) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := takeToken(stream.Context(), store, rateLimitClient(stream.Context()), limit); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
+
+// UnaryRateLimitByIP throttles each peer IP before authentication runs, so
+// every call with bad credentials costs quota. It belongs in front of
+// UnaryAuthenticate.
+func UnaryRateLimitByIP(store ratelimit.Store, limit ratelimit.Limit) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := takeToken(ctx, store, preAuthKeyPrefix+"ip:"+peerIP(ctx), limit); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamRateLimitByIP is UnaryRateLimitByIP for streaming calls.
+func StreamRateLimitByIP(store ratelimit.Store, limit ratelimit.Limit) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := takeToken(stream.Context(), store, preAuthKeyPrefix+"ip:"+peerIP(stream.Context()), limit); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
+
+// takeToken charges key one call and returns ResourceExhausted, with a
+// retry-after header in seconds, when it is over quota. If the store fails
+// the call is let through rather than taking the API down with it.
+func takeToken(ctx context.Context, store ratelimit.Store, key string, limit ratelimit.Limit) error {
+	decision, err := store.Take(ctx, key, limit)
+	if err != nil {
+		slog.WarnContext(ctx, "rate limit store failed", "error", err)
+		return nil
+	}
+	if decision.Allowed {
+		return nil
+	}
+	retryAfter := max(1, int(math.Ceil(decision.RetryAfter.Seconds())))
+	grpc.SetHeader(ctx, metadata.Pairs(metadataRetryAfter, strconv.Itoa(retryAfter)))
+	return status.Error(codes.ResourceExhausted, rateLimitedMessage)
+}
+
+// rateLimitClient falls back to the IP for principals without a name, which
+// would otherwise all share one bucket.
+func rateLimitClient(ctx context.Context) string {
+	if principal, ok := auth.PrincipalFromContext(ctx); ok && principal.Name != "" {
+		return "principal:" + principal.Name
+	}
+	return "ip:" + peerIP(ctx)
+}
+
+// peerIP is the address the call came from, without its port. Connections
+// that aren't TCP, such as in-memory ones, use the address as is.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
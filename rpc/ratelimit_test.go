@@ -0,0 +1,72 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"educabot.com/bookshop/auth"
+	bookshopv1 "educabot.com/bookshop/proto/bookshop/v1"
+	"educabot.com/bookshop/ratelimit"
+	"educabot.com/bookshop/test/mocks"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var testLimit = ratelimit.Limit{Rate: 1, Burst: 1}
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, ratelimit.Limit) (ratelimit.Decision, error) {
+	return ratelimit.Decision{}, errors.New("store unavailable")
+}
+
+func newRateLimitedTestClient(t *testing.T, store ratelimit.Store) bookshopv1.BookMetricsClient {
+	authenticator, err := auth.NewAPIKeyAuthenticator([]auth.APIKey{
+		{Name: "reader", Hash: auth.HashAPIKey(testReaderKey), Scopes: []string{auth.ScopeBooksRead}},
+	})
+	require.NoError(t, err)
+	return newTestClient(t, mocks.NewMockMetricsService().WithMeanUnitsSold(testMeanUnitsSold), mocks.NewMockBooksService(),
+		grpc.ChainUnaryInterceptor(
+			UnaryRateLimitByIP(store, ratelimit.Limit{Rate: 1, Burst: 3}),
+			UnaryAuthenticate(authenticator, auth.ScopeBooksRead),
+			UnaryRateLimit(store, testLimit),
+		),
+	)
+}
+
+func TestUnaryRateLimit_RejectsOverQuota(t *testing.T) {
+	client := newRateLimitedTestClient(t, ratelimit.NewMemoryStore())
+
+	_, err := client.GetMeanUnitsSold(withAPIKey(testReaderKey), &bookshopv1.GetMeanUnitsSoldRequest{})
+	require.NoError(t, err)
+	var header metadata.MD
+	_, err = client.GetMeanUnitsSold(withAPIKey(testReaderKey), &bookshopv1.GetMeanUnitsSoldRequest{}, grpc.Header(&header))
+
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	require.Equal(t, []string{"1"}, header.Get(metadataRetryAfter))
+}
+
+func TestUnaryRateLimitByIP_CountsFailedAuthentication(t *testing.T) {
+	client := newRateLimitedTestClient(t, ratelimit.NewMemoryStore())
+
+	for range 3 {
+		_, err := client.GetMeanUnitsSold(withAPIKey("wrong"), &bookshopv1.GetMeanUnitsSoldRequest{})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	}
+	_, err := client.GetMeanUnitsSold(withAPIKey(testReaderKey), &bookshopv1.GetMeanUnitsSoldRequest{})
+
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestUnaryRateLimit_FailsOpenWhenStoreFails(t *testing.T) {
+	client := newRateLimitedTestClient(t, failingStore{})
+
+	for range 3 {
+		_, err := client.GetMeanUnitsSold(withAPIKey(testReaderKey), &bookshopv1.GetMeanUnitsSoldRequest{})
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,54 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type PanicObserver interface {
+	ObservePanic(route string)
+}
+
+// UnaryRecover turns a panicking handler into an Internal status instead of a
+// crashed process. The panic value and stack are logged with the call's
+// context, and observer counts it under the full method name. It should run
+// inside the logging, metrics and tracing interceptors, so they see the
+// Internal status.
+func UnaryRecover(logger *slog.Logger, observer PanicObserver) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err = recoverPanic(ctx, logger, observer, info.FullMethod, recovered)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamRecover is UnaryRecover for streaming calls.
+func StreamRecover(logger *slog.Logger, observer PanicObserver) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err = recoverPanic(stream.Context(), logger, observer, info.FullMethod, recovered)
+			}
+		}()
+		return handler(srv, stream)
+	}
+}
+
+func recoverPanic(ctx context.Context, logger *slog.Logger, observer PanicObserver, method string, recovered interface{}) error {
+	observer.ObservePanic(method)
+	logger.ErrorContext(ctx, "recovered from panic",
+		slog.String("method", method),
+		slog.String("panic", fmt.Sprint(recovered)),
+		slog.String("stack", string(debug.Stack())),
+	)
+	return status.Error(codes.Internal, internalErrorMessage)
+}
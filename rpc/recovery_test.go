@@ -0,0 +1,64 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"educabot.com/bookshop/logging"
+	bookshopv1 "educabot.com/bookshop/proto/bookshop/v1"
+	"educabot.com/bookshop/service"
+	"educabot.com/bookshop/test/mocks"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testMeanUnitsSoldMethod = "/bookshop.v1.BookMetrics/GetMeanUnitsSold"
+
+type (
+	panickingMetricsService struct {
+		service.MetricsService
+	}
+
+	recordingPanicObserver struct {
+		methods []string
+	}
+)
+
+func (panickingMetricsService) GetMeanUnitsSold(context.Context) (uint, error) {
+	var book *struct{ UnitsSold uint }
+	return book.UnitsSold, nil
+}
+
+func (o *recordingPanicObserver) ObservePanic(method string) {
+	o.methods = append(o.methods, method)
+}
+
+func decodeLogRecord(t *testing.T, out *bytes.Buffer) map[string]interface{} {
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &record))
+	return record
+}
+
+func TestUnaryRecover_ReturnsInternal(t *testing.T) {
+	var out bytes.Buffer
+	observer := &recordingPanicObserver{}
+	logger := logging.NewLogger(&out, logging.Config{Level: slog.LevelInfo})
+	client := newTestClient(t, panickingMetricsService{}, mocks.NewMockBooksService(),
+		grpc.UnaryInterceptor(UnaryRecover(logger, observer)),
+	)
+
+	_, err := client.GetMeanUnitsSold(context.Background(), &bookshopv1.GetMeanUnitsSoldRequest{})
+
+	require.Equal(t, codes.Internal, status.Code(err))
+	require.Equal(t, internalErrorMessage, status.Convert(err).Message())
+	require.Equal(t, []string{testMeanUnitsSoldMethod}, observer.methods)
+	record := decodeLogRecord(t, &out)
+	require.Equal(t, testMeanUnitsSoldMethod, record["method"])
+	require.Contains(t, record["panic"], "nil pointer dereference")
+	require.Contains(t, record["stack"], "recovery_test.go")
+}
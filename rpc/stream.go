@@ -0,0 +1,18 @@
+package rpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// contextStream lets stream interceptors hand the handler a derived context,
+// the way unary interceptors pass one to the next handler.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,101 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	tracerName = "educabot.com/bookshop/rpc"
+
+	attrRPCSystem     = "rpc.system"
+	attrRPCService    = "rpc.service"
+	attrRPCMethod     = "rpc.method"
+	attrRPCStatusCode = "rpc.grpc.status_code"
+
+	rpcSystemGRPC = "grpc"
+)
+
+// metadataCarrier reads and writes trace context in gRPC metadata, whose keys
+// are lowercase like the traceparent header.
+type metadataCarrier metadata.MD
+
+// UnaryTrace starts a server span for every call, continuing any trace the
+// caller passed in its traceparent metadata, so services and upstream calls
+// join the trace.
+func UnaryTrace(tracerProvider trace.TracerProvider, propagator propagation.TextMapPropagator) grpc.UnaryServerInterceptor {
+	tracer := tracerProvider.Tracer(tracerName)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		spanCtx, span := startSpan(ctx, tracer, propagator, info.FullMethod)
+		defer span.End()
+
+		resp, err := handler(spanCtx, req)
+		endSpan(span, err)
+		return resp, err
+	}
+}
+
+// StreamTrace is UnaryTrace for streaming calls.
+func StreamTrace(tracerProvider trace.TracerProvider, propagator propagation.TextMapPropagator) grpc.StreamServerInterceptor {
+	tracer := tracerProvider.Tracer(tracerName)
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		spanCtx, span := startSpan(stream.Context(), tracer, propagator, info.FullMethod)
+		defer span.End()
+
+		err := handler(srv, contextStream{ServerStream: stream, ctx: spanCtx})
+		endSpan(span, err)
+		return err
+	}
+}
+
+func startSpan(ctx context.Context, tracer trace.Tracer, propagator propagation.TextMapPropagator, fullMethod string) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	parent := propagator.Extract(ctx, metadataCarrier(md))
+
+	name := strings.TrimPrefix(fullMethod, "/")
+	service, method, _ := strings.Cut(name, "/")
+	return tracer.Start(parent, name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String(attrRPCSystem, rpcSystemGRPC),
+			attribute.String(attrRPCService, service),
+			attribute.String(attrRPCMethod, method),
+		),
+	)
+}
+
+func endSpan(span trace.Span, err error) {
+	code := status.Code(err)
+	span.SetAttributes(attribute.Int(attrRPCStatusCode, int(code)))
+	if isServerError(code) {
+		span.SetStatus(otelcodes.Error, code.String())
+	}
+}
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,60 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	bookshopv1 "educabot.com/bookshop/proto/bookshop/v1"
+	"educabot.com/bookshop/service"
+	"educabot.com/bookshop/test/mocks"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	testTraceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testParentSpan  = "00f067aa0ba902b7"
+)
+
+func newTracedTestClient(t *testing.T, metricsSvc service.MetricsService) (bookshopv1.BookMetricsClient, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	client := newTestClient(t, metricsSvc, mocks.NewMockBooksService(),
+		grpc.UnaryInterceptor(UnaryTrace(provider, propagation.TraceContext{})),
+	)
+	return client, recorder
+}
+
+func TestUnaryTrace_ContinuesIncomingTrace(t *testing.T) {
+	client, recorder := newTracedTestClient(t, mocks.NewMockMetricsService().WithMeanUnitsSold(testMeanUnitsSold))
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "traceparent", testTraceparent)
+
+	_, err := client.GetMeanUnitsSold(ctx, &bookshopv1.GetMeanUnitsSoldRequest{})
+
+	require.NoError(t, err)
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	require.Equal(t, "bookshop.v1.BookMetrics/GetMeanUnitsSold", spans[0].Name())
+	require.Equal(t, trace.SpanKindServer, spans[0].SpanKind())
+	require.Equal(t, testTraceID, spans[0].SpanContext().TraceID().String())
+	require.Equal(t, testParentSpan, spans[0].Parent().SpanID().String())
+	require.Equal(t, codes.Unset, spans[0].Status().Code)
+}
+
+func TestUnaryTrace_MarksServerErrors(t *testing.T) {
+	client, recorder := newTracedTestClient(t, mocks.NewMockMetricsService().WithError(service.ErrFetchingBooks))
+
+	client.GetMeanUnitsSold(context.Background(), &bookshopv1.GetMeanUnitsSoldRequest{})
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	require.False(t, spans[0].Parent().IsValid())
+	require.Equal(t, codes.Error, spans[0].Status().Code)
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAddress         = "127.0.0.1:0"
+	testShutdownTimeout = time.Second
+	testShortTimeout    = 50 * time.Millisecond
+	testMaxHeaderBytes  = 4096
+	testReadTimeout     = 3 * time.Second
+	hookFirst           = "first"
+	hookSecond          = "second"
+)
+
+var errHook = errors.New("hook failed")
+
+func newTestConfig(shutdownTimeout time.Duration) Config {
+	return Config{
+		Address:         testAddress,
+		ReadTimeout:     testReadTimeout,
+		MaxHeaderBytes:  testMaxHeaderBytes,
+		ShutdownTimeout: shutdownTimeout,
+	}
+}
+
+func startTestServer(t *testing.T, srv *Server) (string, context.CancelFunc, <-chan error) {
+	listener, err := net.Listen("tcp", testAddress)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		result <- srv.Serve(ctx, listener)
+	}()
+	return "http://" + listener.Addr().String(), cancel, result
+}
+
+func TestNew_AppliesConfig(t *testing.T) {
+	cfg := newTestConfig(testShutdownTimeout)
+
+	srv := New(cfg, http.NotFoundHandler())
+
+	require.Equal(t, cfg.Address, srv.httpServer.Addr)
+	require.Equal(t, testReadTimeout, srv.httpServer.ReadTimeout)
+	require.Equal(t, testMaxHeaderBytes, srv.httpServer.MaxHeaderBytes)
+	require.Equal(t, cfg.ShutdownTimeout, srv.shutdownTimeout)
+}
+
+func TestServe_DrainsInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	srv := New(newTestConfig(testShutdownTimeout), http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	url, cancel, result := startTestServer(t, srv)
+	response := make(chan *http.Response, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err == nil {
+			resp.Body.Close()
+		}
+		response <- resp
+	}()
+	<-started
+
+	cancel()
+	close(release)
+
+	resp := <-response
+	require.NotNil(t, resp)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, <-result)
+}
+
+func TestServe_ShutdownDeadlineExceeded(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	srv := New(newTestConfig(testShortTimeout), http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+	}))
+	url, cancel, result := startTestServer(t, srv)
+	go http.Get(url)
+	<-started
+
+	cancel()
+
+	require.ErrorIs(t, <-result, context.DeadlineExceeded)
+}
+
+func TestServe_RunsHooksInReverseOrder(t *testing.T) {
+	srv := New(newTestConfig(testShutdownTimeout), http.NotFoundHandler())
+	var calls []string
+	srv.OnShutdown(func(context.Context) error {
+		calls = append(calls, hookFirst)
+		return nil
+	})
+	srv.OnShutdown(func(context.Context) error {
+		calls = append(calls, hookSecond)
+		return errHook
+	})
+	_, cancel, result := startTestServer(t, srv)
+
+	cancel()
+
+	require.ErrorIs(t, <-result, errHook)
+	require.Equal(t, []string{hookSecond, hookFirst}, calls)
+}
+
+func TestServe_StopsWorkers(t *testing.T) {
+	srv := New(newTestConfig(testShutdownTimeout), http.NotFoundHandler())
+	running := make(chan struct{})
+	stopped := make(chan struct{})
+	srv.Go(func(ctx context.Context) {
+		close(running)
+		<-ctx.Done()
+		close(stopped)
+	})
+	_, cancel, result := startTestServer(t, srv)
+	<-running
+
+	cancel()
+
+	require.NoError(t, <-result)
+	<-stopped
+}
+
+func TestServe_ListenerFailureStopsWorkers(t *testing.T) {
+	srv := New(newTestConfig(testShutdownTimeout), http.NotFoundHandler())
+	stopped := make(chan struct{})
+	srv.Go(func(ctx context.Context) {
+		<-ctx.Done()
+		close(stopped)
+	})
+	listener, err := net.Listen("tcp", testAddress)
+	require.NoError(t, err)
+	listener.Close()
+
+	err = srv.Serve(context.Background(), listener)
+
+	require.Error(t, err)
+	<-stopped
+}
+
+func TestRun_InvalidAddress(t *testing.T) {
+	cfg := newTestConfig(testShutdownTimeout)
+	cfg.Address = "invalid-address"
+	srv := New(cfg, http.NotFoundHandler())
+
+	err := srv.Run(context.Background())
+
+	require.Error(t, err)
+}
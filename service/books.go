@@ -0,0 +1,184 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"educabot.com/bookshop/models"
+	"educabot.com/bookshop/repository"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+type (
+	booksService struct {
+		bookRepo repository.BookRepository
+	}
+
+	BooksService interface {
+		ListBooks(ctx context.Context, query models.BooksQuery) (models.BooksPage, error)
+		FilterBooks(ctx context.Context, filter models.BooksFilter) ([]models.Book, error)
+		GetBook(ctx context.Context, id uint) (models.Book, error)
+		ListAuthors(ctx context.Context) ([]string, error)
+	}
+)
+
+func NewBooksService(bookRepo repository.BookRepository) BooksService {
+	return &booksService{bookRepo: bookRepo}
+}
+
+func (s *booksService) ListBooks(ctx context.Context, query models.BooksQuery) (models.BooksPage, error) {
+	if err := validatePagination(query.Pagination); err != nil {
+		return models.BooksPage{}, err
+	}
+	if err := validateSort(query.Sort); err != nil {
+		return models.BooksPage{}, err
+	}
+	books, err := s.bookRepo.GetBooks(ctx)
+	if err != nil {
+		return models.BooksPage{}, fmt.Errorf("%w: %w", ErrFetchingBooks, err)
+	}
+	books = filterBooks(books, query.Filter)
+	sortBooks(books, query.Sort)
+	return paginate(books, query.Pagination), nil
+}
+
+// FilterBooks returns every book matching filter from a single catalog fetch,
+// for callers such as streams that would otherwise walk ListBooks page by page
+// and could see the catalog change midway.
+func (s *booksService) FilterBooks(ctx context.Context, filter models.BooksFilter) ([]models.Book, error) {
+	books, err := s.bookRepo.GetBooks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFetchingBooks, err)
+	}
+	return filterBooks(books, filter), nil
+}
+
+func (s *booksService) GetBook(ctx context.Context, id uint) (models.Book, error) {
+	books, err := s.bookRepo.GetBooks(ctx)
+	if err != nil {
+		return models.Book{}, fmt.Errorf("%w: %w", ErrFetchingBooks, err)
+	}
+	index := slices.IndexFunc(books, func(book models.Book) bool {
+		return book.ID == id
+	})
+	if index < 0 {
+		return models.Book{}, ErrBookNotFound
+	}
+	return books[index], nil
+}
+
+func (s *booksService) ListAuthors(ctx context.Context) ([]string, error) {
+	books, err := s.bookRepo.GetBooks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFetchingBooks, err)
+	}
+	authors := make([]string, 0, len(books))
+	for _, book := range books {
+		authors = append(authors, book.Author)
+	}
+	slices.Sort(authors)
+	return slices.Compact(authors), nil
+}
+
+func validatePagination(pagination models.Pagination) error {
+	if pagination.Page < 1 {
+		return fmt.Errorf("%w: page must be greater than zero", ErrInvalidPagination)
+	}
+	if pagination.PageSize < 1 || pagination.PageSize > MaxPageSize {
+		return fmt.Errorf("%w: page size must be between 1 and %d", ErrInvalidPagination, MaxPageSize)
+	}
+	return nil
+}
+
+func validateSort(sort models.BooksSort) error {
+	switch sort.Field {
+	case "", models.SortByID, models.SortByName, models.SortByAuthor, models.SortByUnitsSold, models.SortByPrice:
+	default:
+		return fmt.Errorf("%w: unknown field %q", ErrInvalidSort, sort.Field)
+	}
+	switch sort.Direction {
+	case "", models.SortAscending, models.SortDescending:
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown direction %q", ErrInvalidSort, sort.Direction)
+	}
+}
+
+func filterBooks(books []models.Book, filter models.BooksFilter) []models.Book {
+	filtered := make([]models.Book, 0, len(books))
+	for _, book := range books {
+		if matchesFilter(book, filter) {
+			filtered = append(filtered, book)
+		}
+	}
+	return filtered
+}
+
+func matchesFilter(book models.Book, filter models.BooksFilter) bool {
+	if filter.Author != "" && book.Author != filter.Author {
+		return false
+	}
+	if filter.NameContains != "" && !strings.Contains(strings.ToLower(book.Name), strings.ToLower(filter.NameContains)) {
+		return false
+	}
+	if filter.MinPrice != nil && book.Price < *filter.MinPrice {
+		return false
+	}
+	if filter.MaxPrice != nil && book.Price > *filter.MaxPrice {
+		return false
+	}
+	return true
+}
+
+func sortBooks(books []models.Book, sort models.BooksSort) {
+	if sort.Field == "" {
+		return
+	}
+	slices.SortStableFunc(books, func(a, b models.Book) int {
+		result := compareBooks(a, b, sort.Field)
+		if sort.Direction == models.SortDescending {
+			return -result
+		}
+		return result
+	})
+}
+
+func compareBooks(a, b models.Book, field models.SortField) int {
+	switch field {
+	case models.SortByName:
+		return cmp.Compare(a.Name, b.Name)
+	case models.SortByAuthor:
+		return cmp.Compare(a.Author, b.Author)
+	case models.SortByUnitsSold:
+		return cmp.Compare(a.UnitsSold, b.UnitsSold)
+	case models.SortByPrice:
+		return cmp.Compare(a.Price, b.Price)
+	default:
+		return cmp.Compare(a.ID, b.ID)
+	}
+}
+
+func paginate(books []models.Book, pagination models.Pagination) models.BooksPage {
+	total := len(books)
+	// Pages past the end are empty. Checking before multiplying keeps huge
+	// page numbers from overflowing the offset.
+	start := total
+	if pagination.Page-1 <= total/pagination.PageSize {
+		start = min((pagination.Page-1)*pagination.PageSize, total)
+	}
+	end := min(start+pagination.PageSize, total)
+	return models.BooksPage{
+		Books:      books[start:end],
+		Page:       pagination.Page,
+		PageSize:   pagination.PageSize,
+		Total:      total,
+		TotalPages: (total + pagination.PageSize - 1) / pagination.PageSize,
+	}
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"educabot.com/bookshop/models"
+	"educabot.com/bookshop/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testPageSizeTwo  = 2
+	testTotalBooks   = 4
+	testTotalPages   = 2
+	testSecondPage   = 2
+	testPageOutRange = 5
+	testBookIDLion   = uint(4)
+	testBookIDAbsent = uint(99)
+	testNameFragment = "the ring"
+	testMaxPrice     = uint(15)
+	testInvalidField = models.SortField("isbn")
+	testInvalidOrder = models.SortDirection("sideways")
+)
+
+var defaultPagination = models.Pagination{Page: 1, PageSize: DefaultPageSize}
+
+func TestListBooks_FirstPage(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	svc := NewBooksService(repo)
+
+	result, err := svc.ListBooks(context.Background(), models.BooksQuery{Pagination: models.Pagination{Page: 1, PageSize: testPageSizeTwo}})
+
+	require.NoError(t, err)
+	require.Len(t, result.Books, testPageSizeTwo)
+	require.Equal(t, testBookFellowship, result.Books[0].Name)
+	require.Equal(t, testTotalBooks, result.Total)
+	require.Equal(t, testTotalPages, result.TotalPages)
+}
+
+func TestListBooks_SecondPage(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	svc := NewBooksService(repo)
+
+	result, err := svc.ListBooks(context.Background(), models.BooksQuery{Pagination: models.Pagination{Page: testSecondPage, PageSize: testPageSizeTwo}})
+
+	require.NoError(t, err)
+	require.Len(t, result.Books, testPageSizeTwo)
+	require.Equal(t, testBookLion, result.Books[1].Name)
+	require.Equal(t, testSecondPage, result.Page)
+}
+
+func TestListBooks_PageOutOfRange(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	svc := NewBooksService(repo)
+
+	result, err := svc.ListBooks(context.Background(), models.BooksQuery{Pagination: models.Pagination{Page: testPageOutRange, PageSize: testPageSizeTwo}})
+
+	require.NoError(t, err)
+	require.Empty(t, result.Books)
+	require.Equal(t, testTotalBooks, result.Total)
+}
+
+func TestListBooks_HugePageDoesNotOverflow(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	svc := NewBooksService(repo)
+
+	for _, page := range []int{100000000000000000, math.MaxInt} {
+		result, err := svc.ListBooks(context.Background(), models.BooksQuery{Pagination: models.Pagination{Page: page, PageSize: MaxPageSize}})
+
+		require.NoError(t, err)
+		require.Empty(t, result.Books)
+		require.Equal(t, page, result.Page)
+	}
+}
+
+func TestListBooks_InvalidPage(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	svc := NewBooksService(repo)
+
+	_, err := svc.ListBooks(context.Background(), models.BooksQuery{Pagination: models.Pagination{Page: 0, PageSize: DefaultPageSize}})
+
+	require.ErrorIs(t, err, ErrInvalidPagination)
+}
+
+func TestListBooks_PageSizeTooLarge(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	svc := NewBooksService(repo)
+
+	_, err := svc.ListBooks(context.Background(), models.BooksQuery{Pagination: models.Pagination{Page: 1, PageSize: MaxPageSize + 1}})
+
+	require.ErrorIs(t, err, ErrInvalidPagination)
+}
+
+func TestListBooks_RepositoryError(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithError(errRepository)
+	svc := NewBooksService(repo)
+
+	_, err := svc.ListBooks(context.Background(), models.BooksQuery{Pagination: models.Pagination{Page: 1, PageSize: DefaultPageSize}})
+
+	require.ErrorIs(t, err, ErrFetchingBooks)
+	require.ErrorIs(t, err, errRepository)
+}
+
+func TestListBooks_FilterByAuthor(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	svc := NewBooksService(repo)
+
+	result, err := svc.ListBooks(context.Background(), models.BooksQuery{
+		Filter:     models.BooksFilter{Author: testAuthorLewis},
+		Pagination: defaultPagination,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, result.Books, 1)
+	require.Equal(t, testBookLion, result.Books[0].Name)
+	require.Equal(t, 1, result.Total)
+}
+
+func TestListBooks_FilterByNameAndPrice(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	svc := NewBooksService(repo)
+	maxPrice := testMaxPrice
+
+	byName, errByName := svc.ListBooks(context.Background(), models.BooksQuery{
+		Filter:     models.BooksFilter{NameContains: testNameFragment},
+		Pagination: defaultPagination,
+	})
+	byPrice, errByPrice := svc.ListBooks(context.Background(), models.BooksQuery{
+		Filter:     models.BooksFilter{MaxPrice: &maxPrice},
+		Pagination: defaultPagination,
+	})
+
+	require.NoError(t, errByName)
+	require.NoError(t, errByPrice)
+	require.Len(t, byName.Books, 1)
+	require.Equal(t, testBookFellowship, byName.Books[0].Name)
+	require.Len(t, byPrice.Books, 1)
+	require.Equal(t, testBookLion, byPrice.Books[0].Name)
+}
+
+func TestListBooks_SortByPriceAscending(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	svc := NewBooksService(repo)
+
+	result, err := svc.ListBooks(context.Background(), models.BooksQuery{
+		Sort:       models.BooksSort{Field: models.SortByPrice, Direction: models.SortAscending},
+		Pagination: defaultPagination,
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, testBookLion, result.Books[0].Name)
+	require.Equal(t, testBookFellowship, result.Books[1].Name)
+}
+
+func TestListBooks_SortByUnitsSoldDescending(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	svc := NewBooksService(repo)
+
+	result, err := svc.ListBooks(context.Background(), models.BooksQuery{
+		Sort:       models.BooksSort{Field: models.SortByUnitsSold, Direction: models.SortDescending},
+		Pagination: defaultPagination,
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, testBookLion, result.Books[0].Name)
+}
+
+func TestListBooks_SortDoesNotMutateRepository(t *testing.T) {
+	books := newTestBooks()
+	repo := mocks.NewMockBookRepository().WithBooks(books)
+	svc := NewBooksService(repo)
+
+	_, err := svc.ListBooks(context.Background(), models.BooksQuery{
+		Sort:       models.BooksSort{Field: models.SortByName},
+		Pagination: defaultPagination,
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, newTestBooks(), books)
+}
+
+func TestListBooks_InvalidSortField(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	svc := NewBooksService(repo)
+
+	_, err := svc.ListBooks(context.Background(), models.BooksQuery{
+		Sort:       models.BooksSort{Field: testInvalidField},
+		Pagination: defaultPagination,
+	})
+
+	require.ErrorIs(t, err, ErrInvalidSort)
+}
+
+func TestListBooks_InvalidSortDirection(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	svc := NewBooksService(repo)
+
+	_, err := svc.ListBooks(context.Background(), models.BooksQuery{
+		Sort:       models.BooksSort{Field: models.SortByName, Direction: testInvalidOrder},
+		Pagination: defaultPagination,
+	})
+
+	require.ErrorIs(t, err, ErrInvalidSort)
+}
+
+func TestGetBook_Success(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	svc := NewBooksService(repo)
+
+	result, err := svc.GetBook(context.Background(), testBookIDLion)
+
+	require.NoError(t, err)
+	require.Equal(t, testBookLion, result.Name)
+}
+
+func TestGetBook_NotFound(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	svc := NewBooksService(repo)
+
+	_, err := svc.GetBook(context.Background(), testBookIDAbsent)
+
+	require.ErrorIs(t, err, ErrBookNotFound)
+}
+
+func TestFilterBooks_ReturnsEveryMatch(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	svc := NewBooksService(repo)
+
+	result, err := svc.FilterBooks(context.Background(), models.BooksFilter{Author: testAuthorTolkien})
+
+	require.NoError(t, err)
+	require.Len(t, result, 3)
+	for _, book := range result {
+		require.Equal(t, testAuthorTolkien, book.Author)
+	}
+}
+
+func TestFilterBooks_RepositoryError(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithError(errRepository)
+	svc := NewBooksService(repo)
+
+	_, err := svc.FilterBooks(context.Background(), models.BooksFilter{})
+
+	require.ErrorIs(t, err, ErrFetchingBooks)
+}
+
+func TestGetBook_RepositoryError(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithError(errRepository)
+	svc := NewBooksService(repo)
+
+	_, err := svc.GetBook(context.Background(), testBookIDLion)
+
+	require.ErrorIs(t, err, ErrFetchingBooks)
+}
+
+func TestListAuthors_Success(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	svc := NewBooksService(repo)
+
+	result, err := svc.ListAuthors(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, []string{testAuthorLewis, testAuthorTolkien}, result)
+}
+
+func TestListAuthors_RepositoryError(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithError(errRepository)
+	svc := NewBooksService(repo)
+
+	_, err := svc.ListAuthors(context.Background())
+
+	require.ErrorIs(t, err, ErrFetchingBooks)
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"educabot.com/bookshop/models"
+	"educabot.com/bookshop/repository"
+)
+
+type (
+	// RefreshSchedule spaces background refreshes Interval apart, give or take
+	// up to Jitter, so replicas started together don't hit the upstream in step.
+	RefreshSchedule struct {
+		Interval time.Duration
+		Jitter   time.Duration
+	}
+
+	catalogRefresher struct {
+		bookRepo repository.BookRepository
+		schedule RefreshSchedule
+		random   func(n time.Duration) time.Duration
+
+		refreshMu sync.Mutex
+		current   atomic.Pointer[models.CatalogSnapshot]
+	}
+
+	// CatalogRefresher fetches the catalog in the background and serves the
+	// latest snapshot as a BookRepository, so requests never wait on the
+	// upstream.
+	CatalogRefresher interface {
+		repository.BookRepository
+		Run(ctx context.Context)
+		Refresh(ctx context.Context) (*models.CatalogSnapshot, error)
+		Current() (*models.CatalogSnapshot, bool)
+		Seed(books []models.Book, source string, refreshedAt time.Time)
+	}
+)
+
+func NewCatalogRefresher(bookRepo repository.BookRepository, schedule RefreshSchedule) CatalogRefresher {
+	return &catalogRefresher{
+		bookRepo: bookRepo,
+		schedule: schedule,
+		random:   rand.N[time.Duration],
+	}
+}
+
+// Run refreshes right away and then on the schedule until ctx is done. A
+// failed refresh keeps the previous snapshot.
+func (r *catalogRefresher) Run(ctx context.Context) {
+	for {
+		r.Refresh(ctx)
+		timer := time.NewTimer(r.nextDelay())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// Refresh fetches the catalog and publishes it. Concurrent calls, such as a
+// manual refresh during a scheduled one, run one after the other.
+func (r *catalogRefresher) Refresh(ctx context.Context) (*models.CatalogSnapshot, error) {
+	r.refreshMu.Lock()
+	defer r.refreshMu.Unlock()
+
+	fetchCtx, source := repository.WithSource(ctx)
+	books, err := r.bookRepo.GetBooks(fetchCtx)
+	if err != nil {
+		slog.WarnContext(ctx, "catalog refresh failed", "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrFetchingBooks, err)
+	}
+	snapshot := &models.CatalogSnapshot{
+		Books:       slices.Clone(books),
+		Source:      source.Provider(),
+		RefreshedAt: time.Now(),
+	}
+	r.current.Store(snapshot)
+	slog.DebugContext(ctx, "catalog refreshed", "books", len(books), "source", snapshot.Source)
+	return snapshot, nil
+}
+
+// Current returns the published snapshot, or false before the first
+// successful refresh.
+func (r *catalogRefresher) Current() (*models.CatalogSnapshot, bool) {
+	snapshot := r.current.Load()
+	return snapshot, snapshot != nil
+}
+
+// Seed publishes a catalog from before the first refresh, such as one
+// persisted by a previous run. Seeding after a refresh does nothing.
+func (r *catalogRefresher) Seed(books []models.Book, source string, refreshedAt time.Time) {
+	r.current.CompareAndSwap(nil, &models.CatalogSnapshot{
+		Books:       slices.Clone(books),
+		Source:      source,
+		RefreshedAt: refreshedAt,
+	})
+}
+
+// GetBooks serves the current snapshot without any I/O. Callers get their own
+// copy since services sort it in place.
+func (r *catalogRefresher) GetBooks(ctx context.Context) ([]models.Book, error) {
+	snapshot, ok := r.Current()
+	if !ok {
+		return nil, ErrCatalogNotReady
+	}
+	if snapshot.Source != "" {
+		repository.RecordSource(ctx, snapshot.Source)
+	}
+	return slices.Clone(snapshot.Books), nil
+}
+
+func (r *catalogRefresher) nextDelay() time.Duration {
+	if r.schedule.Jitter <= 0 {
+		return r.schedule.Interval
+	}
+	return r.schedule.Interval - r.schedule.Jitter + r.random(2*r.schedule.Jitter+1)
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/models"
+	"educabot.com/bookshop/repository"
+	"educabot.com/bookshop/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testRefreshInterval = time.Minute
+	testRefreshJitter   = 10 * time.Second
+	testSeedSource      = "snapshot"
+)
+
+func newTestRefresher(repo repository.BookRepository) *catalogRefresher {
+	return NewCatalogRefresher(repo, RefreshSchedule{Interval: testRefreshInterval, Jitter: testRefreshJitter}).(*catalogRefresher)
+}
+
+func TestCatalogRefresher_NotReadyBeforeFirstRefresh(t *testing.T) {
+	refresher := newTestRefresher(mocks.NewMockBookRepository().WithBooks(newTestBooks()))
+
+	_, ok := refresher.Current()
+	_, err := refresher.GetBooks(context.Background())
+
+	require.False(t, ok)
+	require.ErrorIs(t, err, ErrCatalogNotReady)
+}
+
+func TestCatalogRefresher_ServesSnapshotWithoutFetching(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	refresher := newTestRefresher(repo)
+	_, err := refresher.Refresh(context.Background())
+	require.NoError(t, err)
+	repo.WithError(errRepository)
+
+	books, err := refresher.GetBooks(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, newTestBooks(), books)
+}
+
+func TestCatalogRefresher_ReadersGetTheirOwnCopy(t *testing.T) {
+	refresher := newTestRefresher(mocks.NewMockBookRepository().WithBooks(newTestBooks()))
+	_, err := refresher.Refresh(context.Background())
+	require.NoError(t, err)
+
+	books, _ := refresher.GetBooks(context.Background())
+	books[0].Price = testNewPrice
+	again, _ := refresher.GetBooks(context.Background())
+
+	require.Equal(t, newTestBooks(), again)
+}
+
+func TestCatalogRefresher_FailedRefreshKeepsSnapshot(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	refresher := newTestRefresher(repo)
+	first, err := refresher.Refresh(context.Background())
+	require.NoError(t, err)
+	repo.WithError(errRepository)
+
+	_, err = refresher.Refresh(context.Background())
+	current, ok := refresher.Current()
+
+	require.ErrorIs(t, err, ErrFetchingBooks)
+	require.ErrorIs(t, err, errRepository)
+	require.True(t, ok)
+	require.Same(t, first, current)
+}
+
+func TestCatalogRefresher_RecordsServingProvider(t *testing.T) {
+	repo := repository.NewFailoverBookRepository([]repository.Provider{
+		{Name: "backup", Priority: 1, Repository: mocks.NewMockBookRepository().WithBooks(newTestBooks())},
+	}, repository.DefaultFailoverCooldown)
+	refresher := newTestRefresher(repo)
+	snapshot, err := refresher.Refresh(context.Background())
+	require.NoError(t, err)
+	ctx, source := repository.WithSource(context.Background())
+
+	_, err = refresher.GetBooks(ctx)
+
+	require.NoError(t, err)
+	require.Equal(t, "backup", snapshot.Source)
+	require.Equal(t, "backup", source.Provider())
+}
+
+func TestCatalogRefresher_SeedOnlyBeforeFirstRefresh(t *testing.T) {
+	refresher := newTestRefresher(mocks.NewMockBookRepository().WithBooks(newTestBooks()))
+	seeded := newTestBooks()[:1]
+	savedAt := time.Now().Add(-time.Hour)
+
+	refresher.Seed(seeded, testSeedSource, savedAt)
+	seededSnapshot, _ := refresher.Current()
+	_, err := refresher.Refresh(context.Background())
+	require.NoError(t, err)
+	refresher.Seed(seeded, testSeedSource, savedAt)
+	current, _ := refresher.Current()
+
+	require.Equal(t, models.CatalogSnapshot{Books: seeded, Source: testSeedSource, RefreshedAt: savedAt}, *seededSnapshot)
+	require.Equal(t, newTestBooks(), current.Books)
+}
+
+func TestCatalogRefresher_NextDelayStaysWithinJitter(t *testing.T) {
+	tests := []struct {
+		name   string
+		random time.Duration
+		want   time.Duration
+	}{
+		{name: "earliest", random: 0, want: testRefreshInterval - testRefreshJitter},
+		{name: "on schedule", random: testRefreshJitter, want: testRefreshInterval},
+		{name: "latest", random: 2 * testRefreshJitter, want: testRefreshInterval + testRefreshJitter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refresher := newTestRefresher(mocks.NewMockBookRepository())
+			refresher.random = func(n time.Duration) time.Duration {
+				require.Equal(t, 2*testRefreshJitter+1, n)
+				return tt.random
+			}
+
+			require.Equal(t, tt.want, refresher.nextDelay())
+		})
+	}
+}
+
+func TestCatalogRefresher_NextDelayWithoutJitter(t *testing.T) {
+	refresher := NewCatalogRefresher(mocks.NewMockBookRepository(), RefreshSchedule{Interval: testRefreshInterval}).(*catalogRefresher)
+
+	require.Equal(t, testRefreshInterval, refresher.nextDelay())
+}
+
+func TestCatalogRefresher_RunRefreshesImmediately(t *testing.T) {
+	refresher := newTestRefresher(mocks.NewMockBookRepository().WithBooks(newTestBooks()))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		refresher.Run(ctx)
+		close(done)
+	}()
+	require.Eventually(t, func() bool {
+		_, ok := refresher.Current()
+		return ok
+	}, time.Second, time.Millisecond)
+	cancel()
+	<-done
+}
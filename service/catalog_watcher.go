@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"educabot.com/bookshop/models"
+	"educabot.com/bookshop/repository"
+)
+
+const subscriberBufferSize = 32
+
+type (
+	catalogWatcher struct {
+		bookRepo repository.BookRepository
+		interval time.Duration
+
+		mu          sync.Mutex
+		snapshot    []models.Book
+		hasSnapshot bool
+		version     models.CatalogVersion
+		subscribers map[chan models.CatalogEvent]struct{}
+	}
+
+	CatalogWatcher interface {
+		Run(ctx context.Context)
+		Subscribe() (<-chan models.CatalogEvent, func())
+		Warm() bool
+		Version() (models.CatalogVersion, bool)
+		Seed(books []models.Book, modifiedAt time.Time)
+	}
+)
+
+func NewCatalogWatcher(bookRepo repository.BookRepository, interval time.Duration) CatalogWatcher {
+	return &catalogWatcher{
+		bookRepo:    bookRepo,
+		interval:    interval,
+		subscribers: make(map[chan models.CatalogEvent]struct{}),
+	}
+}
+
+func (w *catalogWatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		w.poll(ctx)
+		select {
+		case <-ctx.Done():
+			w.closeSubscribers()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *catalogWatcher) Subscribe() (<-chan models.CatalogEvent, func()) {
+	events := make(chan models.CatalogEvent, subscriberBufferSize)
+	w.mu.Lock()
+	w.subscribers[events] = struct{}{}
+	w.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			w.mu.Lock()
+			defer w.mu.Unlock()
+			if _, ok := w.subscribers[events]; ok {
+				delete(w.subscribers, events)
+				close(events)
+			}
+		})
+	}
+	return events, unsubscribe
+}
+
+func (w *catalogWatcher) Warm() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.hasSnapshot
+}
+
+// Version reports the version of the current snapshot, or false before the
+// first successful poll.
+func (w *catalogWatcher) Version() (models.CatalogVersion, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.version, w.hasSnapshot
+}
+
+// Seed installs a catalog from before the first poll, such as one persisted
+// by a previous run, so the watcher is warm right away. The first poll diffs
+// against it like any other. Seeding a warm watcher does nothing.
+func (w *catalogWatcher) Seed(books []models.Book, modifiedAt time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.hasSnapshot {
+		return
+	}
+	w.snapshot = books
+	w.hasSnapshot = true
+	w.version = models.CatalogVersion{Number: w.version.Number + 1, ModifiedAt: modifiedAt}
+}
+
+func (w *catalogWatcher) poll(ctx context.Context) {
+	books, err := w.bookRepo.GetBooks(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "catalog poll failed", "error", err)
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.hasSnapshot {
+		events := diffCatalogs(w.snapshot, books)
+		lagging := 0
+		for _, event := range events {
+			lagging += w.publish(event)
+		}
+		if lagging > 0 {
+			slog.WarnContext(ctx, "closed lagging catalog subscribers", "subscribers", lagging, "buffer", subscriberBufferSize)
+		}
+		slog.DebugContext(ctx, "catalog polled", "books", len(books), "events", len(events))
+	}
+	if !w.hasSnapshot || !slices.Equal(w.snapshot, books) {
+		w.version = models.CatalogVersion{Number: w.version.Number + 1, ModifiedAt: time.Now()}
+	}
+	w.snapshot = books
+	w.hasSnapshot = true
+}
+
+// publish hands event to every subscriber and returns how many it closed.
+// A subscriber whose buffer is full has already missed events, so it is
+// closed rather than silently skipped; consumers resubscribe and start over
+// from the current catalog.
+func (w *catalogWatcher) publish(event models.CatalogEvent) int {
+	lagging := 0
+	for subscriber := range w.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			delete(w.subscribers, subscriber)
+			close(subscriber)
+			lagging++
+		}
+	}
+	return lagging
+}
+
+func (w *catalogWatcher) closeSubscribers() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for subscriber := range w.subscribers {
+		delete(w.subscribers, subscriber)
+		close(subscriber)
+	}
+}
+
+func diffCatalogs(previous, current []models.Book) []models.CatalogEvent {
+	var events []models.CatalogEvent
+	previousByID := indexBooksByID(previous)
+	currentByID := indexBooksByID(current)
+
+	for _, book := range current {
+		old, ok := previousByID[book.ID]
+		if !ok {
+			events = append(events, models.CatalogEvent{Type: models.EventBookAdded, Book: &book})
+			continue
+		}
+		if old.Price != book.Price {
+			events = append(events, models.CatalogEvent{Type: models.EventPriceChanged, Book: &book, Previous: &old})
+		}
+		if old.UnitsSold != book.UnitsSold {
+			events = append(events, models.CatalogEvent{Type: models.EventUnitsSoldChanged, Book: &book, Previous: &old})
+		}
+	}
+	for _, book := range previous {
+		if _, ok := currentByID[book.ID]; !ok {
+			events = append(events, models.CatalogEvent{Type: models.EventBookRemoved, Previous: &book})
+		}
+	}
+
+	return append(events, diffMetrics(previous, current)...)
+}
+
+func diffMetrics(previous, current []models.Book) []models.CatalogEvent {
+	var events []models.CatalogEvent
+	if len(current) == 0 {
+		return events
+	}
+
+	mean := meanUnitsSoldDecimal(current)
+	if len(previous) == 0 || meanUnitsSoldDecimal(previous) != mean {
+		events = append(events, models.CatalogEvent{Type: models.EventMeanUnitsSoldChanged, MeanUnitsSold: &mean})
+	}
+
+	cheapest := cheapestBook(current)
+	if len(previous) == 0 {
+		return append(events, models.CatalogEvent{Type: models.EventCheapestBookChanged, Book: &cheapest})
+	}
+	if previousCheapest := cheapestBook(previous); previousCheapest != cheapest {
+		events = append(events, models.CatalogEvent{Type: models.EventCheapestBookChanged, Book: &cheapest, Previous: &previousCheapest})
+	}
+	return events
+}
+
+func indexBooksByID(books []models.Book) map[uint]models.Book {
+	index := make(map[uint]models.Book, len(books))
+	for _, book := range books {
+		index[book.ID] = book
+	}
+	return index
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/models"
+	"educabot.com/bookshop/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testPollInterval = time.Millisecond
+	testNewPrice     = uint(10)
+	testNewUnitsSold = uint(60000000)
+	testNewBookID    = uint(5)
+	testNewBookName  = "The Hobbit"
+)
+
+func newTestWatcher(repo *mocks.MockBookRepository) *catalogWatcher {
+	return NewCatalogWatcher(repo, testPollInterval).(*catalogWatcher)
+}
+
+func eventTypes(events []models.CatalogEvent) []models.CatalogEventType {
+	types := make([]models.CatalogEventType, 0, len(events))
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func drain(events <-chan models.CatalogEvent) []models.CatalogEvent {
+	var drained []models.CatalogEvent
+	for {
+		select {
+		case event := <-events:
+			drained = append(drained, event)
+		default:
+			return drained
+		}
+	}
+}
+
+func TestDiffCatalogs_NoChanges(t *testing.T) {
+	events := diffCatalogs(newTestBooks(), newTestBooks())
+
+	require.Empty(t, events)
+}
+
+func TestDiffCatalogs_BookAdded(t *testing.T) {
+	current := append(newTestBooks(), models.Book{ID: testNewBookID, Name: testNewBookName, UnitsSold: testNewUnitsSold, Price: testNewPrice})
+
+	events := diffCatalogs(newTestBooks(), current)
+
+	require.Equal(t, []models.CatalogEventType{
+		models.EventBookAdded,
+		models.EventMeanUnitsSoldChanged,
+		models.EventCheapestBookChanged,
+	}, eventTypes(events))
+	require.Equal(t, testNewBookName, events[0].Book.Name)
+	require.Equal(t, testNewBookName, events[2].Book.Name)
+	require.Equal(t, testBookLion, events[2].Previous.Name)
+}
+
+func TestDiffCatalogs_BookRemoved(t *testing.T) {
+	current := newTestBooks()[:3]
+
+	events := diffCatalogs(newTestBooks(), current)
+
+	require.Equal(t, models.EventBookRemoved, events[0].Type)
+	require.Equal(t, testBookLion, events[0].Previous.Name)
+	require.Contains(t, eventTypes(events), models.EventMeanUnitsSoldChanged)
+	require.Contains(t, eventTypes(events), models.EventCheapestBookChanged)
+}
+
+func TestDiffCatalogs_PriceAndUnitsSoldChanged(t *testing.T) {
+	current := newTestBooks()
+	current[1].Price = testNewPrice
+	current[1].UnitsSold = testNewUnitsSold
+
+	events := diffCatalogs(newTestBooks(), current)
+
+	require.Equal(t, []models.CatalogEventType{
+		models.EventPriceChanged,
+		models.EventUnitsSoldChanged,
+		models.EventMeanUnitsSoldChanged,
+		models.EventCheapestBookChanged,
+	}, eventTypes(events))
+	require.Equal(t, testNewPrice, events[0].Book.Price)
+	require.Equal(t, uint(20), events[0].Previous.Price)
+}
+
+func TestDiffCatalogs_FromEmptyCatalog(t *testing.T) {
+	events := diffCatalogs(nil, newTestBooks()[:1])
+
+	require.Equal(t, []models.CatalogEventType{
+		models.EventBookAdded,
+		models.EventMeanUnitsSoldChanged,
+		models.EventCheapestBookChanged,
+	}, eventTypes(events))
+}
+
+func TestCatalogWatcher_FirstPollIsBaseline(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	watcher := newTestWatcher(repo)
+	events, unsubscribe := watcher.Subscribe()
+	defer unsubscribe()
+
+	watcher.poll(context.Background())
+
+	require.Empty(t, drain(events))
+}
+
+func TestCatalogWatcher_PublishesChanges(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	watcher := newTestWatcher(repo)
+	events, unsubscribe := watcher.Subscribe()
+	defer unsubscribe()
+	watcher.poll(context.Background())
+	changed := newTestBooks()
+	changed[0].Price = testNewPrice
+	repo.WithBooks(changed)
+
+	watcher.poll(context.Background())
+
+	received := drain(events)
+	require.Equal(t, models.EventPriceChanged, received[0].Type)
+	require.Contains(t, eventTypes(received), models.EventCheapestBookChanged)
+}
+
+func TestCatalogWatcher_KeepsSnapshotOnRepositoryError(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	watcher := newTestWatcher(repo)
+	events, unsubscribe := watcher.Subscribe()
+	defer unsubscribe()
+	watcher.poll(context.Background())
+	repo.WithError(errRepository)
+
+	watcher.poll(context.Background())
+
+	require.Empty(t, drain(events))
+	require.Equal(t, newTestBooks(), watcher.snapshot)
+}
+
+func TestCatalogWatcher_UnsubscribeClosesChannel(t *testing.T) {
+	watcher := newTestWatcher(mocks.NewMockBookRepository())
+	events, unsubscribe := watcher.Subscribe()
+
+	unsubscribe()
+	unsubscribe()
+
+	_, ok := <-events
+	require.False(t, ok)
+	require.Empty(t, watcher.subscribers)
+}
+
+func TestCatalogWatcher_RunClosesSubscribersOnCancel(t *testing.T) {
+	watcher := newTestWatcher(mocks.NewMockBookRepository().WithBooks(newTestBooks()))
+	events, unsubscribe := watcher.Subscribe()
+	defer unsubscribe()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		watcher.Run(ctx)
+		close(done)
+	}()
+	cancel()
+	<-done
+
+	for range events {
+	}
+	require.Empty(t, watcher.subscribers)
+}
+
+func TestCatalogWatcher_ClosesLaggingSubscribers(t *testing.T) {
+	watcher := newTestWatcher(mocks.NewMockBookRepository())
+	lagging, unsubscribeLagging := watcher.Subscribe()
+	defer unsubscribeLagging()
+	keeping, unsubscribe := watcher.Subscribe()
+	defer unsubscribe()
+	event := models.CatalogEvent{Type: models.EventBookAdded}
+
+	closed := 0
+	for range subscriberBufferSize {
+		closed += watcher.publish(event)
+		<-keeping
+	}
+	closed += watcher.publish(event)
+
+	buffered := 0
+	for range lagging {
+		buffered++
+	}
+	require.Equal(t, 1, closed)
+	require.Equal(t, subscriberBufferSize, buffered)
+	require.Len(t, drain(keeping), 1)
+	require.Len(t, watcher.subscribers, 1)
+}
+
+func TestCatalogWatcher_WarmAfterFirstSuccessfulPoll(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithError(errRepository)
+	watcher := newTestWatcher(repo)
+	watcher.poll(context.Background())
+	cold := watcher.Warm()
+	repo.WithError(nil).WithBooks(newTestBooks())
+
+	watcher.poll(context.Background())
+
+	require.False(t, cold)
+	require.True(t, watcher.Warm())
+}
+
+func TestCatalogWatcher_VersionChangesOnlyWithCatalog(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	watcher := newTestWatcher(repo)
+	_, coldOK := watcher.Version()
+
+	watcher.poll(context.Background())
+	first, _ := watcher.Version()
+	watcher.poll(context.Background())
+	unchanged, _ := watcher.Version()
+	changed := newTestBooks()
+	changed[0].Name = testNewBookName
+	repo.WithBooks(changed)
+	watcher.poll(context.Background())
+	renamed, ok := watcher.Version()
+
+	require.False(t, coldOK)
+	require.True(t, ok)
+	require.Equal(t, uint64(1), first.Number)
+	require.Equal(t, first, unchanged)
+	require.Equal(t, uint64(2), renamed.Number)
+	require.False(t, renamed.ModifiedAt.Before(first.ModifiedAt))
+}
+
+func TestCatalogWatcher_SeedWarmsBeforeFirstPoll(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	watcher := newTestWatcher(repo)
+	events, unsubscribe := watcher.Subscribe()
+	defer unsubscribe()
+	seeded := newTestBooks()
+	seeded[0].Price = testNewPrice
+	savedAt := time.Now().Add(-time.Hour)
+
+	watcher.Seed(seeded, savedAt)
+	version, ok := watcher.Version()
+	watcher.Seed(newTestBooks(), time.Now())
+	watcher.poll(context.Background())
+
+	require.True(t, ok)
+	require.Equal(t, savedAt, version.ModifiedAt)
+	require.Equal(t, models.EventPriceChanged, drain(events)[0].Type)
+	require.Equal(t, newTestBooks(), watcher.snapshot)
+}
@@ -3,7 +3,16 @@ package service
 import "errors"
 
 var (
-	ErrNoBooksFound    = errors.New("no books found")
-	ErrAuthorNotFound  = errors.New("author not found")
-	ErrFetchingBooks   = errors.New("fetching books")
+	ErrNoBooksFound          = errors.New("no books found")
+	ErrAuthorNotFound        = errors.New("author not found")
+	ErrBookNotFound          = errors.New("book not found")
+	ErrFetchingBooks         = errors.New("fetching books")
+	ErrInvalidPagination     = errors.New("invalid pagination")
+	ErrInvalidSort           = errors.New("invalid sort")
+	ErrUnknownMetric         = errors.New("unknown metric")
+	ErrMissingAuthor         = errors.New("missing author")
+	ErrMissingSubscriptionID = errors.New("missing subscription id")
+	ErrTooManySubscriptions  = errors.New("too many subscriptions")
+	ErrSessionClosed         = errors.New("session closed")
+	ErrCatalogNotReady       = errors.New("catalog not loaded yet")
 )
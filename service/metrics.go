@@ -16,6 +16,7 @@ type (
 
 	MetricsService interface {
 		GetMeanUnitsSold(ctx context.Context) (uint, error)
+		GetMeanUnitsSoldDecimal(ctx context.Context) (float64, error)
 		GetCheapestBook(ctx context.Context) (models.Book, error)
 		GetBooksCountByAuthor(ctx context.Context, author string) (uint, error)
 	}
@@ -36,6 +37,17 @@ func (s *metricsService) GetMeanUnitsSold(ctx context.Context) (uint, error) {
 	return meanUnitsSold(books), nil
 }
 
+func (s *metricsService) GetMeanUnitsSoldDecimal(ctx context.Context) (float64, error) {
+	books, err := s.bookRepo.GetBooks(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrFetchingBooks, err)
+	}
+	if len(books) == 0 {
+		return 0, ErrNoBooksFound
+	}
+	return meanUnitsSoldDecimal(books), nil
+}
+
 func (s *metricsService) GetCheapestBook(ctx context.Context) (models.Book, error) {
 	books, err := s.bookRepo.GetBooks(ctx)
 	if err != nil {
@@ -70,6 +82,14 @@ func meanUnitsSold(books []models.Book) uint {
 	return sum / uint(len(books))
 }
 
+func meanUnitsSoldDecimal(books []models.Book) float64 {
+	var sum float64
+	for _, book := range books {
+		sum += float64(book.UnitsSold)
+	}
+	return sum / float64(len(books))
+}
+
 func cheapestBook(books []models.Book) models.Book {
 	return slices.MinFunc(books, func(a, b models.Book) int {
 		return int(a.Price - b.Price)
@@ -18,6 +18,7 @@ var (
 	testAuthorUnknown  = "Unknown Author"
 	testBookFellowship = "The Fellowship of the Ring"
 	testBookLion       = "The Lion, the Witch and the Wardrobe"
+	testDecimalMean    = 1.5
 )
 
 func newTestBooks() []models.Book {
@@ -58,6 +59,35 @@ func TestGetMeanUnitsSold_NoBooksFound(t *testing.T) {
 	require.ErrorIs(t, err, ErrNoBooksFound)
 }
 
+func TestGetMeanUnitsSoldDecimal_Success(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks([]models.Book{{UnitsSold: 1}, {UnitsSold: 2}})
+	svc := NewMetricsService(repo)
+
+	result, err := svc.GetMeanUnitsSoldDecimal(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, testDecimalMean, result)
+}
+
+func TestGetMeanUnitsSoldDecimal_RepositoryError(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithError(errRepository)
+	svc := NewMetricsService(repo)
+
+	_, err := svc.GetMeanUnitsSoldDecimal(context.Background())
+
+	require.ErrorIs(t, err, ErrFetchingBooks)
+	require.ErrorIs(t, err, errRepository)
+}
+
+func TestGetMeanUnitsSoldDecimal_NoBooksFound(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks([]models.Book{})
+	svc := NewMetricsService(repo)
+
+	_, err := svc.GetMeanUnitsSoldDecimal(context.Background())
+
+	require.ErrorIs(t, err, ErrNoBooksFound)
+}
+
 func TestGetCheapestBook_Success(t *testing.T) {
 	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
 	svc := NewMetricsService(repo)
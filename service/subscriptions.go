@@ -0,0 +1,364 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"educabot.com/bookshop/models"
+)
+
+type (
+	subscriptionManager struct {
+		metricsService   MetricsService
+		booksService     BooksService
+		catalogWatcher   CatalogWatcher
+		maxSubscriptions int
+
+		mu       sync.Mutex
+		sessions map[*metricsSession]struct{}
+	}
+
+	metricsSession struct {
+		manager *subscriptionManager
+
+		mu            sync.Mutex
+		subscriptions map[string]models.Subscription
+		lastValues    map[string]interface{}
+		pending       map[string]models.MetricUpdate
+		notify        chan struct{}
+		done          chan struct{}
+		closed        bool
+	}
+
+	subscriptionKey struct {
+		metric   models.Metric
+		author   string
+		maxPrice uint
+		capped   bool
+	}
+
+	metricValue struct {
+		value interface{}
+		err   error
+	}
+
+	SubscriptionManager interface {
+		Run(ctx context.Context)
+		Open() MetricsSession
+	}
+
+	MetricsSession interface {
+		Subscribe(ctx context.Context, subscription models.Subscription) error
+		Unsubscribe(id string)
+		Fail(id string, err error)
+		Next(ctx context.Context) ([]models.MetricUpdate, error)
+		Close()
+	}
+)
+
+func NewSubscriptionManager(metricsService MetricsService, booksService BooksService, catalogWatcher CatalogWatcher, maxSubscriptions int) SubscriptionManager {
+	return &subscriptionManager{
+		metricsService:   metricsService,
+		booksService:     booksService,
+		catalogWatcher:   catalogWatcher,
+		maxSubscriptions: maxSubscriptions,
+		sessions:         make(map[*metricsSession]struct{}),
+	}
+}
+
+func (m *subscriptionManager) Run(ctx context.Context) {
+	events, unsubscribe := m.catalogWatcher.Subscribe()
+	defer func() { unsubscribe() }()
+	defer m.closeSessions()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-events:
+			if !ok {
+				if ctx.Err() != nil {
+					return
+				}
+				// The watcher closes subscribers that fall behind. Events
+				// were lost, so resubscribe and recompute from scratch.
+				unsubscribe()
+				events, unsubscribe = m.catalogWatcher.Subscribe()
+			}
+			drainEvents(events)
+			m.refresh(ctx)
+		}
+	}
+}
+
+func (m *subscriptionManager) Open() MetricsSession {
+	session := &metricsSession{
+		manager:       m,
+		subscriptions: make(map[string]models.Subscription),
+		lastValues:    make(map[string]interface{}),
+		pending:       make(map[string]models.MetricUpdate),
+		notify:        make(chan struct{}, 1),
+		done:          make(chan struct{}),
+	}
+	m.mu.Lock()
+	m.sessions[session] = struct{}{}
+	m.mu.Unlock()
+	return session
+}
+
+func (m *subscriptionManager) refresh(ctx context.Context) {
+	m.mu.Lock()
+	sessions := make([]*metricsSession, 0, len(m.sessions))
+	for session := range m.sessions {
+		sessions = append(sessions, session)
+	}
+	m.mu.Unlock()
+
+	values := make(map[subscriptionKey]metricValue)
+	for _, session := range sessions {
+		for _, subscription := range session.activeSubscriptions() {
+			key := newSubscriptionKey(subscription)
+			result, ok := values[key]
+			if !ok {
+				result.value, result.err = m.compute(ctx, subscription)
+				values[key] = result
+				if result.err != nil {
+					slog.WarnContext(ctx, "subscription metric refresh failed",
+						"metric", subscription.Metric, "author", subscription.Author, "error", result.err)
+				}
+			}
+			if result.err != nil {
+				session.Fail(subscription.ID, result.err)
+				continue
+			}
+			session.offer(subscription, result.value)
+		}
+	}
+}
+
+func (m *subscriptionManager) compute(ctx context.Context, subscription models.Subscription) (interface{}, error) {
+	switch subscription.Metric {
+	case models.MetricMeanUnitsSold:
+		mean, err := m.metricsService.GetMeanUnitsSoldDecimal(ctx)
+		if errors.Is(err, ErrNoBooksFound) {
+			return nil, nil
+		}
+		return mean, err
+	case models.MetricCheapestBook:
+		return m.cheapestBook(ctx, subscription.MaxPrice)
+	case models.MetricCountByAuthor:
+		count, err := m.metricsService.GetBooksCountByAuthor(ctx, subscription.Author)
+		if errors.Is(err, ErrAuthorNotFound) || errors.Is(err, ErrNoBooksFound) {
+			return uint(0), nil
+		}
+		return count, err
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownMetric, subscription.Metric)
+	}
+}
+
+func (m *subscriptionManager) cheapestBook(ctx context.Context, maxPrice *uint) (interface{}, error) {
+	if maxPrice == nil {
+		book, err := m.metricsService.GetCheapestBook(ctx)
+		if errors.Is(err, ErrNoBooksFound) {
+			return nil, nil
+		}
+		return book, err
+	}
+	page, err := m.booksService.ListBooks(ctx, models.BooksQuery{
+		Filter:     models.BooksFilter{MaxPrice: maxPrice},
+		Sort:       models.BooksSort{Field: models.SortByPrice, Direction: models.SortAscending},
+		Pagination: models.Pagination{Page: 1, PageSize: 1},
+	})
+	if err != nil || len(page.Books) == 0 {
+		return nil, err
+	}
+	return page.Books[0], nil
+}
+
+func (m *subscriptionManager) remove(session *metricsSession) {
+	m.mu.Lock()
+	delete(m.sessions, session)
+	m.mu.Unlock()
+}
+
+func (m *subscriptionManager) closeSessions() {
+	m.mu.Lock()
+	sessions := make([]*metricsSession, 0, len(m.sessions))
+	for session := range m.sessions {
+		sessions = append(sessions, session)
+	}
+	m.mu.Unlock()
+	for _, session := range sessions {
+		session.Close()
+	}
+}
+
+func (s *metricsSession) Subscribe(ctx context.Context, subscription models.Subscription) error {
+	if err := validateSubscription(subscription); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrSessionClosed
+	}
+	_, exists := s.subscriptions[subscription.ID]
+	if !exists && s.manager.maxSubscriptions > 0 && len(s.subscriptions) >= s.manager.maxSubscriptions {
+		s.mu.Unlock()
+		return ErrTooManySubscriptions
+	}
+	s.mu.Unlock()
+
+	value, err := s.manager.compute(ctx, subscription)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.subscriptions[subscription.ID] = subscription
+	delete(s.lastValues, subscription.ID)
+	s.mu.Unlock()
+	s.offer(subscription, value)
+	return nil
+}
+
+func (s *metricsSession) Unsubscribe(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.subscriptions, id)
+	delete(s.lastValues, id)
+	delete(s.pending, id)
+}
+
+// Fail queues an error update for id. The last value is forgotten, so the
+// next successful refresh is sent even if the value didn't change.
+func (s *metricsSession) Fail(id string, err error) {
+	s.mu.Lock()
+	delete(s.lastValues, id)
+	s.mu.Unlock()
+	s.enqueue(models.MetricUpdate{SubscriptionID: id, Err: err})
+}
+
+func (s *metricsSession) Next(ctx context.Context) ([]models.MetricUpdate, error) {
+	for {
+		if updates := s.takePending(); len(updates) > 0 {
+			return updates, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-s.done:
+			return nil, ErrSessionClosed
+		case <-s.notify:
+		}
+	}
+}
+
+func (s *metricsSession) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.done)
+	s.mu.Unlock()
+	s.manager.remove(s)
+}
+
+func (s *metricsSession) activeSubscriptions() []models.Subscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	subscriptions := make([]models.Subscription, 0, len(s.subscriptions))
+	for _, subscription := range s.subscriptions {
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions
+}
+
+func (s *metricsSession) offer(subscription models.Subscription, value interface{}) {
+	s.mu.Lock()
+	if _, ok := s.subscriptions[subscription.ID]; !ok {
+		s.mu.Unlock()
+		return
+	}
+	if last, ok := s.lastValues[subscription.ID]; ok && reflect.DeepEqual(last, value) {
+		s.mu.Unlock()
+		return
+	}
+	s.lastValues[subscription.ID] = value
+	s.mu.Unlock()
+	s.enqueue(models.MetricUpdate{SubscriptionID: subscription.ID, Metric: subscription.Metric, Value: value})
+}
+
+func (s *metricsSession) enqueue(update models.MetricUpdate) {
+	s.mu.Lock()
+	s.pending[update.SubscriptionID] = update
+	s.mu.Unlock()
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (s *metricsSession) takePending() []models.MetricUpdate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.pending) == 0 {
+		return nil
+	}
+	updates := make([]models.MetricUpdate, 0, len(s.pending))
+	for id, update := range s.pending {
+		updates = append(updates, update)
+		delete(s.pending, id)
+	}
+	slices.SortFunc(updates, func(a, b models.MetricUpdate) int {
+		return strings.Compare(a.SubscriptionID, b.SubscriptionID)
+	})
+	return updates
+}
+
+func validateSubscription(subscription models.Subscription) error {
+	if subscription.ID == "" {
+		return ErrMissingSubscriptionID
+	}
+	switch subscription.Metric {
+	case models.MetricMeanUnitsSold, models.MetricCheapestBook:
+		return nil
+	case models.MetricCountByAuthor:
+		if subscription.Author == "" {
+			return ErrMissingAuthor
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownMetric, subscription.Metric)
+	}
+}
+
+func newSubscriptionKey(subscription models.Subscription) subscriptionKey {
+	key := subscriptionKey{metric: subscription.Metric, author: subscription.Author}
+	if subscription.MaxPrice != nil {
+		key.maxPrice = *subscription.MaxPrice
+		key.capped = true
+	}
+	return key
+}
+
+func drainEvents(events <-chan models.CatalogEvent) {
+	for {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/models"
+	"educabot.com/bookshop/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSubscriptionMean     = "mean"
+	testSubscriptionCount    = "count"
+	testSubscriptionCheapest = "cheapest"
+	testMaxSubscriptions     = 2
+	testNextTimeout          = time.Second
+	testPriceCap             = uint(18)
+	testUnknownMetric        = models.Metric("median")
+)
+
+func newTestManager(repo *mocks.MockBookRepository, watcher CatalogWatcher) *subscriptionManager {
+	return NewSubscriptionManager(NewMetricsService(repo), NewBooksService(repo), watcher, testMaxSubscriptions).(*subscriptionManager)
+}
+
+func nextUpdates(t *testing.T, session MetricsSession) []models.MetricUpdate {
+	ctx, cancel := context.WithTimeout(context.Background(), testNextTimeout)
+	defer cancel()
+	updates, err := session.Next(ctx)
+	require.NoError(t, err)
+	return updates
+}
+
+func requireNoUpdates(t *testing.T, session MetricsSession) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := session.Next(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestSubscribe_SendsInitialValue(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	manager := newTestManager(repo, mocks.NewMockCatalogWatcher())
+	session := manager.Open()
+
+	err := session.Subscribe(context.Background(), models.Subscription{ID: testSubscriptionCount, Metric: models.MetricCountByAuthor, Author: testAuthorTolkien})
+
+	require.NoError(t, err)
+	updates := nextUpdates(t, session)
+	require.Len(t, updates, 1)
+	require.Equal(t, testSubscriptionCount, updates[0].SubscriptionID)
+	require.Equal(t, uint(3), updates[0].Value)
+}
+
+func TestSubscribe_CheapestUnderPriceCap(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	manager := newTestManager(repo, mocks.NewMockCatalogWatcher())
+	session := manager.Open()
+	priceCap := testPriceCap
+
+	err := session.Subscribe(context.Background(), models.Subscription{ID: testSubscriptionCheapest, Metric: models.MetricCheapestBook, MaxPrice: &priceCap})
+
+	require.NoError(t, err)
+	updates := nextUpdates(t, session)
+	require.Equal(t, testBookLion, updates[0].Value.(models.Book).Name)
+}
+
+func TestSubscribe_UnknownAuthorCountsZero(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	manager := newTestManager(repo, mocks.NewMockCatalogWatcher())
+	session := manager.Open()
+
+	err := session.Subscribe(context.Background(), models.Subscription{ID: testSubscriptionCount, Metric: models.MetricCountByAuthor, Author: testAuthorUnknown})
+
+	require.NoError(t, err)
+	require.Equal(t, uint(0), nextUpdates(t, session)[0].Value)
+}
+
+func TestSubscribe_ValidationErrors(t *testing.T) {
+	manager := newTestManager(mocks.NewMockBookRepository(), mocks.NewMockCatalogWatcher())
+	session := manager.Open()
+
+	errMissingID := session.Subscribe(context.Background(), models.Subscription{Metric: models.MetricMeanUnitsSold})
+	errUnknown := session.Subscribe(context.Background(), models.Subscription{ID: testSubscriptionMean, Metric: testUnknownMetric})
+	errAuthor := session.Subscribe(context.Background(), models.Subscription{ID: testSubscriptionCount, Metric: models.MetricCountByAuthor})
+
+	require.ErrorIs(t, errMissingID, ErrMissingSubscriptionID)
+	require.ErrorIs(t, errUnknown, ErrUnknownMetric)
+	require.ErrorIs(t, errAuthor, ErrMissingAuthor)
+}
+
+func TestSubscribe_TooManySubscriptions(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	manager := newTestManager(repo, mocks.NewMockCatalogWatcher())
+	session := manager.Open()
+	require.NoError(t, session.Subscribe(context.Background(), models.Subscription{ID: testSubscriptionMean, Metric: models.MetricMeanUnitsSold}))
+	require.NoError(t, session.Subscribe(context.Background(), models.Subscription{ID: testSubscriptionCheapest, Metric: models.MetricCheapestBook}))
+
+	err := session.Subscribe(context.Background(), models.Subscription{ID: testSubscriptionCount, Metric: models.MetricCountByAuthor, Author: testAuthorLewis})
+
+	require.ErrorIs(t, err, ErrTooManySubscriptions)
+}
+
+func TestSubscribe_RepositoryError(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithError(errRepository)
+	manager := newTestManager(repo, mocks.NewMockCatalogWatcher())
+	session := manager.Open()
+
+	err := session.Subscribe(context.Background(), models.Subscription{ID: testSubscriptionMean, Metric: models.MetricMeanUnitsSold})
+
+	require.ErrorIs(t, err, ErrFetchingBooks)
+}
+
+func TestRefresh_SendsOnlyChangedValues(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	manager := newTestManager(repo, mocks.NewMockCatalogWatcher())
+	session := manager.Open()
+	require.NoError(t, session.Subscribe(context.Background(), models.Subscription{ID: testSubscriptionMean, Metric: models.MetricMeanUnitsSold}))
+	require.NoError(t, session.Subscribe(context.Background(), models.Subscription{ID: testSubscriptionCount, Metric: models.MetricCountByAuthor, Author: testAuthorTolkien}))
+	nextUpdates(t, session)
+	changed := newTestBooks()
+	changed[3].UnitsSold = 0
+	repo.WithBooks(changed)
+
+	manager.refresh(context.Background())
+
+	updates := nextUpdates(t, session)
+	require.Len(t, updates, 1)
+	require.Equal(t, testSubscriptionMean, updates[0].SubscriptionID)
+	manager.refresh(context.Background())
+	requireNoUpdates(t, session)
+}
+
+func TestRefresh_FailureSendsErrorAndResendsOnRecovery(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	manager := newTestManager(repo, mocks.NewMockCatalogWatcher())
+	session := manager.Open()
+	require.NoError(t, session.Subscribe(context.Background(), models.Subscription{ID: testSubscriptionMean, Metric: models.MetricMeanUnitsSold}))
+	initial := nextUpdates(t, session)
+	repo.WithError(errRepository)
+
+	manager.refresh(context.Background())
+	failed := nextUpdates(t, session)
+	repo.WithError(nil)
+	manager.refresh(context.Background())
+	recovered := nextUpdates(t, session)
+
+	require.Len(t, failed, 1)
+	require.ErrorIs(t, failed[0].Err, ErrFetchingBooks)
+	require.Equal(t, initial, recovered)
+}
+
+func TestRefresh_ConflatesPendingUpdates(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	manager := newTestManager(repo, mocks.NewMockCatalogWatcher())
+	session := manager.Open()
+	require.NoError(t, session.Subscribe(context.Background(), models.Subscription{ID: testSubscriptionMean, Metric: models.MetricMeanUnitsSold}))
+	changed := newTestBooks()
+	changed[0].UnitsSold = 0
+	repo.WithBooks(changed)
+
+	manager.refresh(context.Background())
+
+	updates := nextUpdates(t, session)
+	require.Len(t, updates, 1)
+	require.Equal(t, meanUnitsSoldDecimal(changed), updates[0].Value)
+}
+
+func TestUnsubscribe_StopsUpdates(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	manager := newTestManager(repo, mocks.NewMockCatalogWatcher())
+	session := manager.Open()
+	require.NoError(t, session.Subscribe(context.Background(), models.Subscription{ID: testSubscriptionMean, Metric: models.MetricMeanUnitsSold}))
+
+	session.Unsubscribe(testSubscriptionMean)
+
+	requireNoUpdates(t, session)
+}
+
+func TestFail_QueuesErrorUpdate(t *testing.T) {
+	manager := newTestManager(mocks.NewMockBookRepository(), mocks.NewMockCatalogWatcher())
+	session := manager.Open()
+
+	session.Fail(testSubscriptionMean, ErrUnknownMetric)
+
+	updates := nextUpdates(t, session)
+	require.Equal(t, testSubscriptionMean, updates[0].SubscriptionID)
+	require.ErrorIs(t, updates[0].Err, ErrUnknownMetric)
+}
+
+func TestClose_EndsSessionAndDetaches(t *testing.T) {
+	manager := newTestManager(mocks.NewMockBookRepository(), mocks.NewMockCatalogWatcher())
+	session := manager.Open()
+
+	session.Close()
+	session.Close()
+
+	_, err := session.Next(context.Background())
+	require.ErrorIs(t, err, ErrSessionClosed)
+	require.ErrorIs(t, session.Subscribe(context.Background(), models.Subscription{ID: testSubscriptionMean, Metric: models.MetricMeanUnitsSold}), ErrSessionClosed)
+	require.Empty(t, manager.sessions)
+}
+
+func TestRun_RecomputesOnCatalogEvents(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	events := make(chan models.CatalogEvent, 1)
+	watcher := mocks.NewMockCatalogWatcher().WithLiveEvents(events)
+	manager := newTestManager(repo, watcher)
+	session := manager.Open()
+	require.NoError(t, session.Subscribe(context.Background(), models.Subscription{ID: testSubscriptionCount, Metric: models.MetricCountByAuthor, Author: testAuthorLewis}))
+	nextUpdates(t, session)
+	repo.WithBooks(newTestBooks()[:3])
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		manager.Run(ctx)
+		close(done)
+	}()
+	events <- models.CatalogEvent{Type: models.EventBookRemoved}
+
+	updates := nextUpdates(t, session)
+	require.Equal(t, uint(0), updates[0].Value)
+	cancel()
+	<-done
+	require.True(t, watcher.Unsubscribed)
+	_, err := session.Next(context.Background())
+	require.ErrorIs(t, err, ErrSessionClosed)
+}
+
+func TestRun_ResubscribesWhenDroppedAsLagging(t *testing.T) {
+	repo := mocks.NewMockBookRepository().WithBooks(newTestBooks())
+	watcher := newTestWatcher(repo)
+	manager := newTestManager(repo, watcher)
+	session := manager.Open()
+	require.NoError(t, session.Subscribe(context.Background(), models.Subscription{ID: testSubscriptionCount, Metric: models.MetricCountByAuthor, Author: testAuthorLewis}))
+	nextUpdates(t, session)
+	repo.WithBooks(newTestBooks()[:3])
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go manager.Run(ctx)
+	require.Eventually(t, func() bool { return subscriberCount(watcher) == 1 }, time.Second, time.Millisecond)
+	watcher.mu.Lock()
+	for subscriber := range watcher.subscribers {
+		delete(watcher.subscribers, subscriber)
+		close(subscriber)
+	}
+	watcher.mu.Unlock()
+
+	updates := nextUpdates(t, session)
+	require.Equal(t, uint(0), updates[0].Value)
+	require.Eventually(t, func() bool { return subscriberCount(watcher) == 1 }, time.Second, time.Millisecond)
+}
+
+func subscriberCount(watcher *catalogWatcher) int {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	return len(watcher.subscribers)
+}
@@ -0,0 +1,223 @@
+package telemetry
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"educabot.com/bookshop/repository"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	namespace = "bookshop"
+
+	labelRoute     = "route"
+	labelMethod    = "method"
+	labelStatus    = "status"
+	labelCode      = "code"
+	labelOutcome   = "outcome"
+	labelErrorType = "error_type"
+	labelReason    = "reason"
+	labelKind      = "kind"
+
+	outcomeSuccess = "success"
+	outcomeError   = "error"
+
+	ErrorTypeCreatingRequest  = "creating_request"
+	ErrorTypeExecutingRequest = "executing_request"
+	ErrorTypeUnexpectedStatus = "unexpected_status"
+	ErrorTypeDecodingResponse = "decoding_response"
+	ErrorTypeResponseTooLarge = "response_too_large"
+	ErrorTypeInvalidCatalog   = "invalid_catalog"
+	ErrorTypeOther            = "other"
+)
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests     *prometheus.CounterVec
+	httpDuration     *prometheus.HistogramVec
+	rpcRequests      *prometheus.CounterVec
+	rpcDuration      *prometheus.HistogramVec
+	upstreamDuration *prometheus.HistogramVec
+	upstreamErrors   *prometheus.CounterVec
+	catalogSize      prometheus.Gauge
+	panics           *prometheus.CounterVec
+	invalidBooks     *prometheus.CounterVec
+	coercions        *prometheus.CounterVec
+
+	cacheHits   atomic.Uint64
+	cacheMisses atomic.Uint64
+}
+
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests served, by route, method and status code.",
+		}, []string{labelRoute, labelMethod, labelStatus}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency, by route, method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{labelRoute, labelMethod, labelStatus}),
+		rpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_requests_total",
+			Help:      "gRPC calls served, by full method and status code.",
+		}, []string{labelMethod, labelCode}),
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "grpc_request_duration_seconds",
+			Help:      "gRPC call latency, by full method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{labelMethod, labelCode}),
+		upstreamDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "upstream_get_books_duration_seconds",
+			Help:      "Latency of upstream GetBooks calls, by outcome.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{labelOutcome}),
+		upstreamErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "upstream_get_books_errors_total",
+			Help:      "Failed upstream GetBooks calls, by error type.",
+		}, []string{labelErrorType}),
+		catalogSize: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "catalog_size",
+			Help:      "Number of books in the last catalog fetched from upstream.",
+		}),
+		panics: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_panics_total",
+			Help:      "Handler panics recovered, by route.",
+		}, []string{labelRoute}),
+		invalidBooks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "upstream_invalid_books_total",
+			Help:      "Validation problems found in upstream books, by reason.",
+		}, []string{labelReason}),
+		coercions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "upstream_coerced_values_total",
+			Help:      "Upstream book values accepted by lenient decoding, by kind of coercion.",
+		}, []string{labelKind}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.rpcRequests,
+		m.rpcDuration,
+		m.upstreamDuration,
+		m.upstreamErrors,
+		m.catalogSize,
+		m.panics,
+		m.invalidBooks,
+		m.coercions,
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_hits_total",
+			Help:      "Upstream fetches answered with 304 and served from the cached catalog.",
+		}, func() float64 { return float64(m.cacheHits.Load()) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_misses_total",
+			Help:      "Upstream fetches that downloaded and decoded the full catalog.",
+		}, func() float64 { return float64(m.cacheMisses.Load()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cache_hit_ratio",
+			Help:      "Fraction of successful upstream fetches served from the cached catalog.",
+		}, m.cacheHitRatio),
+	)
+	return m
+}
+
+// Handler exposes the collected metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+func (m *Metrics) ObserveRequest(route, method string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(route, method, code).Inc()
+	m.httpDuration.WithLabelValues(route, method, code).Observe(duration.Seconds())
+}
+
+func (m *Metrics) ObserveRPC(method, code string, duration time.Duration) {
+	m.rpcRequests.WithLabelValues(method, code).Inc()
+	m.rpcDuration.WithLabelValues(method, code).Observe(duration.Seconds())
+}
+
+func (m *Metrics) ObservePanic(route string) {
+	m.panics.WithLabelValues(route).Inc()
+}
+
+func (m *Metrics) ObserveUpstream(duration time.Duration, err error) {
+	if err != nil {
+		m.upstreamDuration.WithLabelValues(outcomeError).Observe(duration.Seconds())
+		m.upstreamErrors.WithLabelValues(upstreamErrorType(err)).Inc()
+		return
+	}
+	m.upstreamDuration.WithLabelValues(outcomeSuccess).Observe(duration.Seconds())
+}
+
+func (m *Metrics) SetCatalogSize(size int) {
+	m.catalogSize.Set(float64(size))
+}
+
+func (m *Metrics) RecordCacheHit() {
+	m.cacheHits.Add(1)
+}
+
+func (m *Metrics) RecordCacheMiss() {
+	m.cacheMisses.Add(1)
+}
+
+func (m *Metrics) RecordInvalidBook(reason string) {
+	m.invalidBooks.WithLabelValues(reason).Inc()
+}
+
+func (m *Metrics) RecordCoercion(kind string) {
+	m.coercions.WithLabelValues(kind).Inc()
+}
+
+func (m *Metrics) cacheHitRatio() float64 {
+	hits := m.cacheHits.Load()
+	total := hits + m.cacheMisses.Load()
+	if total == 0 {
+		return 0
+	}
+	return float64(hits) / float64(total)
+}
+
+func upstreamErrorType(err error) string {
+	switch {
+	case errors.Is(err, repository.ErrCreatingRequest):
+		return ErrorTypeCreatingRequest
+	case errors.Is(err, repository.ErrExecutingRequest):
+		return ErrorTypeExecutingRequest
+	case errors.Is(err, repository.ErrUnexpectedStatus):
+		return ErrorTypeUnexpectedStatus
+	case errors.Is(err, repository.ErrResponseTooLarge):
+		return ErrorTypeResponseTooLarge
+	case errors.Is(err, repository.ErrInvalidCatalog):
+		return ErrorTypeInvalidCatalog
+	case errors.Is(err, repository.ErrDecodingResponse):
+		return ErrorTypeDecodingResponse
+	default:
+		return ErrorTypeOther
+	}
+}
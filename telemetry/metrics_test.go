@@ -0,0 +1,141 @@
+package telemetry
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/repository"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testRoute    = "/v2/books"
+	testDuration = 20 * time.Millisecond
+
+	testRPCMethod = "/bookshop.v1.BookMetrics/GetMeanUnitsSold"
+)
+
+func TestObserveRequest(t *testing.T) {
+	m := NewMetrics()
+
+	m.ObserveRequest(testRoute, http.MethodGet, http.StatusOK, testDuration)
+	m.ObserveRequest(testRoute, http.MethodGet, http.StatusOK, testDuration)
+	m.ObserveRequest(testRoute, http.MethodGet, http.StatusNotFound, testDuration)
+
+	require.Equal(t, 2.0, testutil.ToFloat64(m.httpRequests.WithLabelValues(testRoute, http.MethodGet, "200")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.httpRequests.WithLabelValues(testRoute, http.MethodGet, "404")))
+	require.Equal(t, 2, testutil.CollectAndCount(m.httpDuration))
+}
+
+func TestObserveRPC(t *testing.T) {
+	m := NewMetrics()
+
+	m.ObserveRPC(testRPCMethod, "OK", testDuration)
+	m.ObserveRPC(testRPCMethod, "NotFound", testDuration)
+
+	require.Equal(t, 1.0, testutil.ToFloat64(m.rpcRequests.WithLabelValues(testRPCMethod, "OK")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.rpcRequests.WithLabelValues(testRPCMethod, "NotFound")))
+	require.Equal(t, 2, testutil.CollectAndCount(m.rpcDuration))
+}
+
+func TestObserveUpstream_ErrorTypes(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"executing request", fmt.Errorf("%w: timeout", repository.ErrExecutingRequest), ErrorTypeExecutingRequest},
+		{"unexpected status", fmt.Errorf("%w: 500", repository.ErrUnexpectedStatus), ErrorTypeUnexpectedStatus},
+		{"decoding response", fmt.Errorf("%w: EOF", repository.ErrDecodingResponse), ErrorTypeDecodingResponse},
+		{"response too large", fmt.Errorf("%w: limit is 10 bytes", repository.ErrResponseTooLarge), ErrorTypeResponseTooLarge},
+		{"invalid catalog", fmt.Errorf("%w: 2 invalid books", repository.ErrInvalidCatalog), ErrorTypeInvalidCatalog},
+		{"creating request", repository.ErrCreatingRequest, ErrorTypeCreatingRequest},
+		{"unknown", errors.New("boom"), ErrorTypeOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMetrics()
+
+			m.ObserveUpstream(testDuration, tt.err)
+
+			require.Equal(t, 1.0, testutil.ToFloat64(m.upstreamErrors.WithLabelValues(tt.expected)))
+			require.Equal(t, 1, testutil.CollectAndCount(m.upstreamErrors))
+		})
+	}
+}
+
+func TestObserveUpstream_SuccessRecordsNoError(t *testing.T) {
+	m := NewMetrics()
+
+	m.ObserveUpstream(testDuration, nil)
+
+	require.Equal(t, 0, testutil.CollectAndCount(m.upstreamErrors))
+	require.Equal(t, 1, testutil.CollectAndCount(m.upstreamDuration))
+}
+
+func TestCacheHitRatio(t *testing.T) {
+	m := NewMetrics()
+	require.Equal(t, 0.0, m.cacheHitRatio())
+
+	m.RecordCacheHit()
+	m.RecordCacheHit()
+	m.RecordCacheHit()
+	m.RecordCacheMiss()
+
+	require.Equal(t, 0.75, m.cacheHitRatio())
+}
+
+func TestHandler_ExposesTextFormat(t *testing.T) {
+	m := NewMetrics()
+	m.SetCatalogSize(3)
+	m.ObserveRequest(testRoute, http.MethodGet, http.StatusOK, testDuration)
+	server := httptest.NewServer(m.Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.True(t, strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain"))
+	require.Contains(t, string(body), "bookshop_catalog_size 3")
+	require.Contains(t, string(body), `bookshop_http_requests_total{method="GET",route="/v2/books",status="200"} 1`)
+	require.Contains(t, string(body), "bookshop_cache_hit_ratio 0")
+}
+
+func TestObservePanic(t *testing.T) {
+	m := NewMetrics()
+
+	m.ObservePanic(testRoute)
+
+	require.Equal(t, 1.0, testutil.ToFloat64(m.panics.WithLabelValues(testRoute)))
+}
+
+func TestRecordInvalidBook(t *testing.T) {
+	m := NewMetrics()
+
+	m.RecordInvalidBook(repository.ReasonMissingField)
+	m.RecordInvalidBook(repository.ReasonMissingField)
+	m.RecordInvalidBook(repository.ReasonDuplicateID)
+
+	require.Equal(t, 2.0, testutil.ToFloat64(m.invalidBooks.WithLabelValues(repository.ReasonMissingField)))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.invalidBooks.WithLabelValues(repository.ReasonDuplicateID)))
+}
+
+func TestRecordCoercion(t *testing.T) {
+	m := NewMetrics()
+
+	m.RecordCoercion(repository.CoercionRounded)
+
+	require.Equal(t, 1.0, testutil.ToFloat64(m.coercions.WithLabelValues(repository.CoercionRounded)))
+}
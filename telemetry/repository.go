@@ -0,0 +1,30 @@
+package telemetry
+
+import (
+	"context"
+	"time"
+
+	"educabot.com/bookshop/models"
+	"educabot.com/bookshop/repository"
+)
+
+type instrumentedBookRepository struct {
+	repo    repository.BookRepository
+	metrics *Metrics
+}
+
+// InstrumentBookRepository records latency, errors and catalog size for every
+// GetBooks call made through repo.
+func InstrumentBookRepository(repo repository.BookRepository, metrics *Metrics) repository.BookRepository {
+	return &instrumentedBookRepository{repo: repo, metrics: metrics}
+}
+
+func (r *instrumentedBookRepository) GetBooks(ctx context.Context) ([]models.Book, error) {
+	start := time.Now()
+	books, err := r.repo.GetBooks(ctx)
+	r.metrics.ObserveUpstream(time.Since(start), err)
+	if err == nil {
+		r.metrics.SetCatalogSize(len(books))
+	}
+	return books, err
+}
@@ -0,0 +1,39 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"educabot.com/bookshop/models"
+	"educabot.com/bookshop/repository"
+	"educabot.com/bookshop/test/mocks"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstrumentBookRepository_Success(t *testing.T) {
+	m := NewMetrics()
+	books := []models.Book{{ID: 1, Name: "The Hobbit"}, {ID: 2, Name: "Dune"}}
+	repo := InstrumentBookRepository(mocks.NewMockBookRepository().WithBooks(books), m)
+
+	got, err := repo.GetBooks(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, books, got)
+	require.Equal(t, 2.0, testutil.ToFloat64(m.catalogSize))
+	require.Equal(t, 0, testutil.CollectAndCount(m.upstreamErrors))
+}
+
+func TestInstrumentBookRepository_Error(t *testing.T) {
+	m := NewMetrics()
+	m.SetCatalogSize(5)
+	upstreamErr := fmt.Errorf("%w: 503", repository.ErrUnexpectedStatus)
+	repo := InstrumentBookRepository(mocks.NewMockBookRepository().WithError(upstreamErr), m)
+
+	_, err := repo.GetBooks(context.Background())
+
+	require.ErrorIs(t, err, repository.ErrUnexpectedStatus)
+	require.Equal(t, 5.0, testutil.ToFloat64(m.catalogSize))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.upstreamErrors.WithLabelValues(ErrorTypeUnexpectedStatus)))
+}
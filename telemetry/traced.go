@@ -0,0 +1,118 @@
+package telemetry
+
+import (
+	"context"
+
+	"educabot.com/bookshop/models"
+	"educabot.com/bookshop/repository"
+	"educabot.com/bookshop/service"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	attrAuthor   = "bookshop.author"
+	attrBookID   = "bookshop.book_id"
+	attrPage     = "bookshop.page"
+	attrPageSize = "bookshop.page_size"
+	attrBooks    = "bookshop.books"
+)
+
+type (
+	tracedBookRepository struct {
+		repo   repository.BookRepository
+		tracer trace.Tracer
+	}
+
+	tracedMetricsService struct {
+		svc    service.MetricsService
+		tracer trace.Tracer
+	}
+
+	tracedBooksService struct {
+		svc    service.BooksService
+		tracer trace.Tracer
+	}
+)
+
+func TraceBookRepository(repo repository.BookRepository, tracerProvider trace.TracerProvider) repository.BookRepository {
+	return &tracedBookRepository{repo: repo, tracer: tracerProvider.Tracer(tracerName)}
+}
+
+func (r *tracedBookRepository) GetBooks(ctx context.Context) ([]models.Book, error) {
+	ctx, span := r.tracer.Start(ctx, "BookRepository.GetBooks", trace.WithSpanKind(trace.SpanKindClient))
+	books, err := r.repo.GetBooks(ctx)
+	span.SetAttributes(attribute.Int(attrBooks, len(books)))
+	endSpan(span, err)
+	return books, err
+}
+
+func TraceMetricsService(svc service.MetricsService, tracerProvider trace.TracerProvider) service.MetricsService {
+	return &tracedMetricsService{svc: svc, tracer: tracerProvider.Tracer(tracerName)}
+}
+
+func (s *tracedMetricsService) GetMeanUnitsSold(ctx context.Context) (uint, error) {
+	ctx, span := s.tracer.Start(ctx, "MetricsService.GetMeanUnitsSold")
+	mean, err := s.svc.GetMeanUnitsSold(ctx)
+	endSpan(span, err)
+	return mean, err
+}
+
+func (s *tracedMetricsService) GetMeanUnitsSoldDecimal(ctx context.Context) (float64, error) {
+	ctx, span := s.tracer.Start(ctx, "MetricsService.GetMeanUnitsSoldDecimal")
+	mean, err := s.svc.GetMeanUnitsSoldDecimal(ctx)
+	endSpan(span, err)
+	return mean, err
+}
+
+func (s *tracedMetricsService) GetCheapestBook(ctx context.Context) (models.Book, error) {
+	ctx, span := s.tracer.Start(ctx, "MetricsService.GetCheapestBook")
+	book, err := s.svc.GetCheapestBook(ctx)
+	endSpan(span, err)
+	return book, err
+}
+
+func (s *tracedMetricsService) GetBooksCountByAuthor(ctx context.Context, author string) (uint, error) {
+	ctx, span := s.tracer.Start(ctx, "MetricsService.GetBooksCountByAuthor",
+		trace.WithAttributes(attribute.String(attrAuthor, author)))
+	count, err := s.svc.GetBooksCountByAuthor(ctx, author)
+	endSpan(span, err)
+	return count, err
+}
+
+func TraceBooksService(svc service.BooksService, tracerProvider trace.TracerProvider) service.BooksService {
+	return &tracedBooksService{svc: svc, tracer: tracerProvider.Tracer(tracerName)}
+}
+
+func (s *tracedBooksService) ListBooks(ctx context.Context, query models.BooksQuery) (models.BooksPage, error) {
+	ctx, span := s.tracer.Start(ctx, "BooksService.ListBooks", trace.WithAttributes(
+		attribute.Int(attrPage, query.Pagination.Page),
+		attribute.Int(attrPageSize, query.Pagination.PageSize),
+	))
+	page, err := s.svc.ListBooks(ctx, query)
+	endSpan(span, err)
+	return page, err
+}
+
+func (s *tracedBooksService) FilterBooks(ctx context.Context, filter models.BooksFilter) ([]models.Book, error) {
+	ctx, span := s.tracer.Start(ctx, "BooksService.FilterBooks")
+	books, err := s.svc.FilterBooks(ctx, filter)
+	span.SetAttributes(attribute.Int(attrBooks, len(books)))
+	endSpan(span, err)
+	return books, err
+}
+
+func (s *tracedBooksService) GetBook(ctx context.Context, id uint) (models.Book, error) {
+	ctx, span := s.tracer.Start(ctx, "BooksService.GetBook",
+		trace.WithAttributes(attribute.Int64(attrBookID, int64(id))))
+	book, err := s.svc.GetBook(ctx, id)
+	endSpan(span, err)
+	return book, err
+}
+
+func (s *tracedBooksService) ListAuthors(ctx context.Context) ([]string, error) {
+	ctx, span := s.tracer.Start(ctx, "BooksService.ListAuthors")
+	authors, err := s.svc.ListAuthors(ctx)
+	endSpan(span, err)
+	return authors, err
+}
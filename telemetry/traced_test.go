@@ -0,0 +1,57 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"educabot.com/bookshop/models"
+	"educabot.com/bookshop/service"
+	"educabot.com/bookshop/test/mocks"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var errTraced = errors.New("upstream down")
+
+func TestTraceMetricsService_NestsRepositorySpan(t *testing.T) {
+	provider, recorder := newRecordingProvider()
+	books := []models.Book{{ID: 1, Name: "Dune", Author: "Frank Herbert", UnitsSold: 10, Price: 5}}
+	repo := TraceBookRepository(mocks.NewMockBookRepository().WithBooks(books), provider)
+	svc := TraceMetricsService(service.NewMetricsService(repo), provider)
+
+	_, err := svc.GetBooksCountByAuthor(context.Background(), "Frank Herbert")
+
+	require.NoError(t, err)
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	require.Equal(t, "BookRepository.GetBooks", spans[0].Name())
+	require.Equal(t, "MetricsService.GetBooksCountByAuthor", spans[1].Name())
+	require.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+}
+
+func TestTraceBookRepository_RecordsError(t *testing.T) {
+	provider, recorder := newRecordingProvider()
+	repo := TraceBookRepository(mocks.NewMockBookRepository().WithError(errTraced), provider)
+
+	_, err := repo.GetBooks(context.Background())
+
+	require.ErrorIs(t, err, errTraced)
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	require.Equal(t, codes.Error, spans[0].Status().Code)
+	require.Equal(t, errTraced.Error(), spans[0].Status().Description)
+}
+
+func TestTraceBooksService_Spans(t *testing.T) {
+	provider, recorder := newRecordingProvider()
+	svc := TraceBooksService(mocks.NewMockBooksService().WithAuthors([]string{"Frank Herbert"}), provider)
+
+	authors, err := svc.ListAuthors(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"Frank Herbert"}, authors)
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	require.Equal(t, "BooksService.ListAuthors", spans[0].Name())
+}
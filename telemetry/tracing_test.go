@@ -0,0 +1,65 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const headerTraceparent = "traceparent"
+
+func newRecordingProvider() (*sdktrace.TracerProvider, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	return sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)), recorder
+}
+
+func TestNewTracerProvider_UnknownExporter(t *testing.T) {
+	_, err := NewTracerProvider(context.Background(), TracingConfig{Exporter: "jaeger"}, nil)
+
+	require.ErrorIs(t, err, ErrUnknownExporter)
+}
+
+func TestNewTracerProvider_StdoutExporter(t *testing.T) {
+	var out bytes.Buffer
+	provider, err := NewTracerProvider(context.Background(), TracingConfig{
+		Exporter:    ExporterStdout,
+		ServiceName: "bookshop",
+		SampleRatio: 1,
+	}, &out)
+	require.NoError(t, err)
+
+	_, span := provider.Tracer(tracerName).Start(context.Background(), "test-span")
+	span.End()
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	require.Contains(t, out.String(), "test-span")
+}
+
+func TestTracedTransport_InjectsTraceparent(t *testing.T) {
+	provider, recorder := newRecordingProvider()
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get(headerTraceparent)
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: TracedTransport(http.DefaultTransport, provider, NewPropagator())}
+	ctx, parent := provider.Tracer(tracerName).Start(context.Background(), "parent")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	parent.End()
+
+	require.Contains(t, received, parent.SpanContext().TraceID().String())
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	require.Equal(t, parent.SpanContext().TraceID(), spans[0].SpanContext().TraceID())
+}
@@ -2,15 +2,25 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"educabot.com/bookshop/models"
 )
 
 type MockMetricsService struct {
-	MeanUnitsSold uint
-	CheapestBook  models.Book
-	BooksCount    uint
-	Err           error
+	MeanUnitsSold        uint
+	MeanUnitsSoldDecimal float64
+	CheapestBook         models.Book
+	BooksCount           uint
+	Err                  error
+}
+
+type MockBooksService struct {
+	BooksPage models.BooksPage
+	Books     []models.Book
+	Book      models.Book
+	Authors   []string
+	Err       error
 }
 
 func NewMockMetricsService() *MockMetricsService {
@@ -22,6 +32,11 @@ func (m *MockMetricsService) WithMeanUnitsSold(mean uint) *MockMetricsService {
 	return m
 }
 
+func (m *MockMetricsService) WithMeanUnitsSoldDecimal(mean float64) *MockMetricsService {
+	m.MeanUnitsSoldDecimal = mean
+	return m
+}
+
 func (m *MockMetricsService) WithCheapestBook(book models.Book) *MockMetricsService {
 	m.CheapestBook = book
 	return m
@@ -41,6 +56,10 @@ func (m *MockMetricsService) GetMeanUnitsSold(_ context.Context) (uint, error) {
 	return m.MeanUnitsSold, m.Err
 }
 
+func (m *MockMetricsService) GetMeanUnitsSoldDecimal(_ context.Context) (float64, error) {
+	return m.MeanUnitsSoldDecimal, m.Err
+}
+
 func (m *MockMetricsService) GetCheapestBook(_ context.Context) (models.Book, error) {
 	return m.CheapestBook, m.Err
 }
@@ -48,3 +67,157 @@ func (m *MockMetricsService) GetCheapestBook(_ context.Context) (models.Book, er
 func (m *MockMetricsService) GetBooksCountByAuthor(_ context.Context, _ string) (uint, error) {
 	return m.BooksCount, m.Err
 }
+
+func NewMockBooksService() *MockBooksService {
+	return &MockBooksService{}
+}
+
+func (m *MockBooksService) WithBooksPage(page models.BooksPage) *MockBooksService {
+	m.BooksPage = page
+	return m
+}
+
+func (m *MockBooksService) WithBooks(books []models.Book) *MockBooksService {
+	m.Books = books
+	return m
+}
+
+func (m *MockBooksService) WithBook(book models.Book) *MockBooksService {
+	m.Book = book
+	return m
+}
+
+func (m *MockBooksService) WithAuthors(authors []string) *MockBooksService {
+	m.Authors = authors
+	return m
+}
+
+func (m *MockBooksService) WithError(err error) *MockBooksService {
+	m.Err = err
+	return m
+}
+
+func (m *MockBooksService) ListBooks(_ context.Context, _ models.BooksQuery) (models.BooksPage, error) {
+	return m.BooksPage, m.Err
+}
+
+func (m *MockBooksService) FilterBooks(_ context.Context, _ models.BooksFilter) ([]models.Book, error) {
+	return m.Books, m.Err
+}
+
+func (m *MockBooksService) GetBook(_ context.Context, _ uint) (models.Book, error) {
+	return m.Book, m.Err
+}
+
+func (m *MockBooksService) ListAuthors(_ context.Context) ([]string, error) {
+	return m.Authors, m.Err
+}
+
+type MockCatalogWatcher struct {
+	Events       []models.CatalogEvent
+	Live         chan models.CatalogEvent
+	IsWarm       bool
+	Snapshot     models.CatalogVersion
+	Seeded       []models.Book
+	Unsubscribed bool
+}
+
+func NewMockCatalogWatcher() *MockCatalogWatcher {
+	return &MockCatalogWatcher{}
+}
+
+func (m *MockCatalogWatcher) WithEvents(events []models.CatalogEvent) *MockCatalogWatcher {
+	m.Events = events
+	return m
+}
+
+func (m *MockCatalogWatcher) WithLiveEvents(events chan models.CatalogEvent) *MockCatalogWatcher {
+	m.Live = events
+	return m
+}
+
+func (m *MockCatalogWatcher) WithWarm(warm bool) *MockCatalogWatcher {
+	m.IsWarm = warm
+	return m
+}
+
+func (m *MockCatalogWatcher) WithVersion(version models.CatalogVersion) *MockCatalogWatcher {
+	m.Snapshot = version
+	m.IsWarm = true
+	return m
+}
+
+func (m *MockCatalogWatcher) Warm() bool {
+	return m.IsWarm
+}
+
+func (m *MockCatalogWatcher) Version() (models.CatalogVersion, bool) {
+	return m.Snapshot, m.IsWarm
+}
+
+func (m *MockCatalogWatcher) Seed(books []models.Book, modifiedAt time.Time) {
+	m.Seeded = books
+	m.Snapshot = models.CatalogVersion{Number: m.Snapshot.Number + 1, ModifiedAt: modifiedAt}
+	m.IsWarm = true
+}
+
+func (m *MockCatalogWatcher) Run(_ context.Context) {}
+
+func (m *MockCatalogWatcher) Subscribe() (<-chan models.CatalogEvent, func()) {
+	if m.Live != nil {
+		return m.Live, func() { m.Unsubscribed = true }
+	}
+	events := make(chan models.CatalogEvent, len(m.Events))
+	for _, event := range m.Events {
+		events <- event
+	}
+	close(events)
+	return events, func() { m.Unsubscribed = true }
+}
+
+type MockCatalogRefresher struct {
+	Snapshot  *models.CatalogSnapshot
+	Err       error
+	Refreshes int
+}
+
+func NewMockCatalogRefresher() *MockCatalogRefresher {
+	return &MockCatalogRefresher{}
+}
+
+func (m *MockCatalogRefresher) WithSnapshot(snapshot *models.CatalogSnapshot) *MockCatalogRefresher {
+	m.Snapshot = snapshot
+	return m
+}
+
+func (m *MockCatalogRefresher) WithError(err error) *MockCatalogRefresher {
+	m.Err = err
+	return m
+}
+
+func (m *MockCatalogRefresher) Run(_ context.Context) {}
+
+func (m *MockCatalogRefresher) Refresh(_ context.Context) (*models.CatalogSnapshot, error) {
+	m.Refreshes++
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Snapshot, nil
+}
+
+func (m *MockCatalogRefresher) Current() (*models.CatalogSnapshot, bool) {
+	return m.Snapshot, m.Snapshot != nil
+}
+
+func (m *MockCatalogRefresher) Seed(books []models.Book, source string, refreshedAt time.Time) {
+	if m.Snapshot == nil {
+		m.Snapshot = &models.CatalogSnapshot{Books: books, Source: source, RefreshedAt: refreshedAt}
+	}
+}
+
+func (m *MockCatalogRefresher) GetBooks(_ context.Context) ([]models.Book, error) {
+	if m.Snapshot == nil {
+		return nil, m.Err
+	}
+	return m.Snapshot.Books, nil
+}